@@ -55,6 +55,16 @@ func (err *PanicError) Error() string {
 	return fmt.Sprintf("panic: exception=%v || stack=\n%s", err.exception, err.stack)
 }
 
+// Exception 返回 recover 得到的值
+func (err *PanicError) Exception() any {
+	return err.exception
+}
+
+// Stack 返回 panic 时的调用栈
+func (err *PanicError) Stack() string {
+	return err.stack
+}
+
 func RecoverPanic(panicError *error, onPanicErrors ...func(*PanicError)) error {
 	if exception := recover(); exception != nil {
 		err := NewPanicError(exception, GetStackTrace())
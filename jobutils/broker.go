@@ -28,6 +28,7 @@ type SmartHandler struct {
 	deadline time.Duration   // 控制截止时间
 	merge    bool            // 合并执行
 
+	timeMutex       sync.Mutex // 异步执行时保护以下时间
 	lastCallingTime time.Time
 	lastCalledTime  time.Time
 }
@@ -106,9 +107,15 @@ func (handler *SmartHandler) call(callingTime time.Time) {
 		return
 	}
 
+	handler.timeMutex.Lock()
 	handler.lastCallingTime = time.Now()
+	handler.timeMutex.Unlock()
+
 	handler.callback()
+
+	handler.timeMutex.Lock()
 	handler.lastCalledTime = time.Now()
+	handler.timeMutex.Unlock()
 }
 
 func (handler *SmartHandler) callAsync() {
@@ -142,6 +149,9 @@ func (handler *SmartHandler) merged(callingTime time.Time) bool {
 		return false
 	}
 
+	handler.timeMutex.Lock()
+	defer handler.timeMutex.Unlock()
+
 	return handler.lastCallingTime.After(callingTime)
 }
 
@@ -160,7 +170,11 @@ func (handler *SmartHandler) doWait(callingTime time.Time) bool {
 	}
 
 	// 确保间隔
-	if left := handler.interval - now.Sub(handler.lastCalledTime); left > waitDuration {
+	handler.timeMutex.Lock()
+	lastCalledTime := handler.lastCalledTime
+	handler.timeMutex.Unlock()
+
+	if left := handler.interval - now.Sub(lastCalledTime); left > waitDuration {
 		waitDuration = left
 	}
 
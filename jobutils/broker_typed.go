@@ -0,0 +1,269 @@
+package jobutils
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/stl"
+)
+
+// MatchTopic 判断主题是否匹配订阅模式，两者均按 separator 分层：
+// * 匹配恰好一层，# 匹配零或多层，如 order.*.paid 匹配 order.42.paid，order.# 匹配 order 与 order.42.paid
+func MatchTopic(pattern, topic, separator string) bool {
+	return matchTopicSegments(strings.Split(pattern, separator), strings.Split(topic, separator))
+}
+
+func matchTopicSegments(pattern, topic []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "#":
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for i := 0; i <= len(topic); i++ {
+				if matchTopicSegments(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(topic) == 0 {
+				return false
+			}
+		default:
+			if len(topic) == 0 || pattern[0] != topic[0] {
+				return false
+			}
+		}
+
+		pattern, topic = pattern[1:], topic[1:]
+	}
+
+	return len(topic) == 0
+}
+
+// TopicMessage 为发布到主题的一条消息
+type TopicMessage[Payload any] struct {
+	Topic   string
+	Payload Payload
+	Time    time.Time
+}
+
+// typedSubscription 将消息暂存，由 SmartHandler 调度投递：
+// 不合并时每次投递最早的一条；合并时投递全部暂存的消息，或只投递最新的一条
+type typedSubscription[Payload any] struct {
+	pattern  string
+	segments []string
+	handler  *SmartHandler
+	batch    bool
+	callback func([]TopicMessage[Payload])
+
+	mutex   sync.Mutex
+	pending []TopicMessage[Payload]
+}
+
+func (subscription *typedSubscription[Payload]) push(message TopicMessage[Payload]) {
+	subscription.mutex.Lock()
+	subscription.pending = append(subscription.pending, message)
+	subscription.mutex.Unlock()
+
+	subscription.handler.Call()
+}
+
+func (subscription *typedSubscription[Payload]) deliver() {
+	subscription.mutex.Lock()
+
+	// 逾期的消息丢弃
+	if deadline := subscription.handler.deadline; deadline > 0 {
+		subscription.pending = stl.Purge(subscription.pending, func(message TopicMessage[Payload]) bool {
+			return time.Since(message.Time) > deadline
+		})
+	}
+
+	var messages []TopicMessage[Payload]
+	switch n := len(subscription.pending); {
+	case n == 0:
+	case !subscription.handler.merge:
+		messages = subscription.pending[:1:1]
+		subscription.pending = subscription.pending[1:]
+	case subscription.batch:
+		messages = subscription.pending
+		subscription.pending = nil
+	default:
+		messages = subscription.pending[n-1:]
+		subscription.pending = nil
+	}
+
+	subscription.mutex.Unlock()
+
+	if len(messages) > 0 {
+		subscription.callback(messages)
+	}
+}
+
+// TypedBroker 为携带数据的 TopicBroker，订阅模式支持 * 与 # 通配符（见 MatchTopic）；
+// 订阅者的异步、合并、间隔、并发等控制与 TopicBroker 一致
+type TypedBroker[Payload any] struct {
+	mutex         sync.RWMutex
+	separator     string
+	subscriptions []*typedSubscription[Payload]
+}
+
+// NewTypedBroker 创建以 . 分层的 TypedBroker
+func NewTypedBroker[Payload any]() *TypedBroker[Payload] {
+	return &TypedBroker[Payload]{
+		separator: ".",
+	}
+}
+
+// WithSeparator 设置主题分层的分隔符，须在订阅前调用
+func (broker *TypedBroker[Payload]) WithSeparator(separator string) *TypedBroker[Payload] {
+	broker.separator = separator
+	return broker
+}
+
+// Publish 发布消息，返回匹配的订阅个数；同步订阅者在返回前执行完毕
+func (broker *TypedBroker[Payload]) Publish(topic string, payload Payload) int {
+	message := TopicMessage[Payload]{
+		Topic:   topic,
+		Payload: payload,
+		Time:    time.Now(),
+	}
+
+	segments := strings.Split(topic, broker.separator)
+
+	broker.mutex.RLock()
+	matched := stl.Filter(broker.subscriptions, func(subscription *typedSubscription[Payload]) bool {
+		return matchTopicSegments(subscription.segments, segments)
+	})
+	broker.mutex.RUnlock()
+
+	// 锁外投递，订阅者可在回调中订阅或发布
+	for _, subscription := range matched {
+		subscription.push(message)
+	}
+
+	return len(matched)
+}
+
+// Subscribe 订阅匹配 pattern 的主题，合并时只收到最新的一条
+func (broker *TypedBroker[Payload]) Subscribe(pattern string, callback func(TopicMessage[Payload])) *TypedBrokerSubscribing[Payload] {
+	return broker.newSubscribing(pattern, false, func(messages []TopicMessage[Payload]) {
+		callback(messages[0])
+	})
+}
+
+// SubscribeBatch 订阅匹配 pattern 的主题，合并时一次收到全部被合并的消息，按发布先后排列
+func (broker *TypedBroker[Payload]) SubscribeBatch(pattern string, callback func([]TopicMessage[Payload])) *TypedBrokerSubscribing[Payload] {
+	return broker.newSubscribing(pattern, true, callback)
+}
+
+func (broker *TypedBroker[Payload]) newSubscribing(pattern string, batch bool, callback func([]TopicMessage[Payload])) *TypedBrokerSubscribing[Payload] {
+	subscription := &typedSubscription[Payload]{
+		pattern:  pattern,
+		segments: strings.Split(pattern, broker.separator),
+		batch:    batch,
+		callback: callback,
+	}
+
+	subscription.handler = &SmartHandler{
+		callback: subscription.deliver,
+		ctx:      bgCtx,
+	}
+
+	return &TypedBrokerSubscribing[Payload]{
+		broker:       broker,
+		subscription: subscription,
+	}
+}
+
+func (broker *TypedBroker[Payload]) subscribe(subscription *typedSubscription[Payload]) *TypedBroker[Payload] {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.subscriptions = append(broker.subscriptions, subscription)
+
+	return broker
+}
+
+func (broker *TypedBroker[Payload]) purge(f func(*typedSubscription[Payload]) bool) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.subscriptions = stl.Purge(broker.subscriptions, f)
+}
+
+// UnsubscribeByIdent 取消以 pattern 订阅且标识为 ident 的订阅
+func (broker *TypedBroker[Payload]) UnsubscribeByIdent(pattern string, ident string) {
+	broker.purge(func(subscription *typedSubscription[Payload]) bool {
+		return subscription.pattern == pattern && subscription.handler.ident == ident
+	})
+}
+
+// Unsubscribe 取消以 pattern 订阅的全部订阅
+func (broker *TypedBroker[Payload]) Unsubscribe(pattern string) {
+	broker.purge(func(subscription *typedSubscription[Payload]) bool {
+		return subscription.pattern == pattern
+	})
+}
+
+// Subscriptions 返回订阅个数
+func (broker *TypedBroker[Payload]) Subscriptions() int {
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	return len(broker.subscriptions)
+}
+
+type TypedBrokerSubscribing[Payload any] struct {
+	broker       *TypedBroker[Payload]
+	subscription *typedSubscription[Payload]
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithIdent(ident string) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.ident = ident
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithAsync(async bool) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.async = async
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithCtx(ctx context.Context) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.ctx = ctx
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithConcurrentcy(n int) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.tickets = NewJobTokens(n)
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithDelay(delay time.Duration) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.delay = delay
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithInterval(interval time.Duration) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.interval = interval
+	return subscribing
+}
+
+// WithDeadline 设置消息的有效期，逾期未投递的消息被丢弃
+func (subscribing *TypedBrokerSubscribing[Payload]) WithDeadline(deadline time.Duration) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.deadline = deadline
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) WithMerge(merge bool) *TypedBrokerSubscribing[Payload] {
+	subscribing.subscription.handler.merge = merge
+	return subscribing
+}
+
+func (subscribing *TypedBrokerSubscribing[Payload]) Done() *TypedBroker[Payload] {
+	return subscribing.broker.subscribe(subscribing.subscription)
+}
@@ -0,0 +1,170 @@
+package jobutils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTopic(t *testing.T) {
+	for _, c := range []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"order.paid", "order.paid", true},
+		{"order.paid", "order.created", false},
+		{"order.*", "order.paid", true},
+		{"order.*", "order", false},
+		{"order.*", "order.42.paid", false},
+		{"order.*.paid", "order.42.paid", true},
+		{"order.#", "order", true},
+		{"order.#", "order.42.paid", true},
+		{"#", "anything.at.all", true},
+		{"#.paid", "order.42.paid", true},
+		{"#.paid", "paid", true},
+		{"order.#.paid", "order.paid", true},
+		{"order.#.paid", "order.1.2.paid", true},
+		{"order.#.paid", "order.1.2.created", false},
+		{"*.*", "order", false},
+	} {
+		assert.Equal(t, c.match, MatchTopic(c.pattern, c.topic, "."), "%s ~ %s", c.pattern, c.topic)
+	}
+
+	assert.True(t, MatchTopic("order/+/paid", "order/+/paid", "/"))
+	assert.True(t, MatchTopic("order/*/paid", "order/42/paid", "/"))
+}
+
+func TestTypedBrokerSync(t *testing.T) {
+	broker := NewTypedBroker[int]()
+
+	var exact, wildcard []TopicMessage[int]
+	broker.Subscribe("order.paid", func(message TopicMessage[int]) {
+		exact = append(exact, message)
+	}).Done()
+	broker.Subscribe("order.#", func(message TopicMessage[int]) {
+		wildcard = append(wildcard, message)
+	}).WithIdent("audit").Done()
+
+	assert.Equal(t, 2, broker.Publish("order.paid", 1))
+	assert.Equal(t, 1, broker.Publish("order.created", 2))
+	assert.Equal(t, 0, broker.Publish("user.created", 3))
+
+	assert.Len(t, exact, 1)
+	assert.Equal(t, "order.paid", exact[0].Topic)
+	assert.Equal(t, 1, exact[0].Payload)
+	assert.Equal(t, []int{1, 2}, []int{wildcard[0].Payload, wildcard[1].Payload})
+
+	broker.UnsubscribeByIdent("order.#", "other")
+	assert.Equal(t, 2, broker.Subscriptions())
+	broker.UnsubscribeByIdent("order.#", "audit")
+	assert.Equal(t, 1, broker.Publish("order.paid", 4))
+	broker.Unsubscribe("order.paid")
+	assert.Equal(t, 0, broker.Subscriptions())
+
+	// 回调中可以发布
+	broker.Subscribe("ping", func(message TopicMessage[int]) {
+		broker.Publish("pong", message.Payload+1)
+	}).Done()
+	var pong int
+	broker.Subscribe("pong", func(message TopicMessage[int]) {
+		pong = message.Payload
+	}).Done()
+	broker.Publish("ping", 10)
+	assert.Equal(t, 11, pong)
+}
+
+func testTypedBrokerMerge(t *testing.T, batch bool) [][]int {
+	broker := NewTypedBroker[int]()
+
+	var mutex sync.Mutex
+	var deliveries [][]int
+	record := func(messages []TopicMessage[int]) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		payloads := make([]int, 0, len(messages))
+		for _, message := range messages {
+			payloads = append(payloads, message.Payload)
+		}
+		deliveries = append(deliveries, payloads)
+	}
+
+	var subscribing *TypedBrokerSubscribing[int]
+	if batch {
+		subscribing = broker.SubscribeBatch("metrics.*", record)
+	} else {
+		subscribing = broker.Subscribe("metrics.*", func(message TopicMessage[int]) {
+			record([]TopicMessage[int]{message})
+		})
+	}
+	subscribing.
+		WithAsync(true).
+		WithConcurrentcy(1).
+		WithInterval(50 * time.Millisecond).
+		WithMerge(true).
+		Done()
+
+	for i := 1; i <= 5; i++ {
+		broker.Publish("metrics.cpu", i)
+		time.Sleep(2 * time.Millisecond)
+	}
+	time.Sleep(120 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return deliveries
+}
+
+func TestTypedBrokerMergeLatest(t *testing.T) {
+	deliveries := testTypedBrokerMerge(t, false)
+
+	assert.Less(t, len(deliveries), 5)
+	assert.Equal(t, []int{5}, deliveries[len(deliveries)-1])
+	for _, payloads := range deliveries {
+		assert.Len(t, payloads, 1)
+	}
+}
+
+func TestTypedBrokerMergeBatch(t *testing.T) {
+	deliveries := testTypedBrokerMerge(t, true)
+
+	assert.Less(t, len(deliveries), 5)
+
+	var all []int
+	for _, payloads := range deliveries {
+		all = append(all, payloads...)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, all)
+}
+
+func TestTypedBrokerAsyncDeadline(t *testing.T) {
+	broker := NewTypedBroker[string]()
+
+	var mutex sync.Mutex
+	var received []string
+	broker.Subscribe("job.#", func(message TopicMessage[string]) {
+		mutex.Lock()
+		received = append(received, message.Payload)
+		mutex.Unlock()
+		time.Sleep(20 * time.Millisecond)
+	}).
+		WithAsync(true).
+		WithConcurrentcy(1).
+		WithDeadline(30 * time.Millisecond).
+		Done()
+
+	for _, payload := range []string{"a", "b", "c", "d"} {
+		broker.Publish("job.done", payload)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	assert.NotEmpty(t, received)
+	assert.Less(t, len(received), 4)
+}
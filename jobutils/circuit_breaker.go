@@ -0,0 +1,417 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+)
+
+var (
+	ErrCircuitOpen            = errors.New("jobutils: circuit breaker is open")
+	ErrCircuitTooManyRequests = errors.New("jobutils: circuit breaker is half-open and busy")
+)
+
+type CircuitState int
+
+const (
+	// CircuitClosed 正常放行，统计失败情况
+	CircuitClosed CircuitState = iota
+	// CircuitOpen 熔断，直接拒绝，openTimeout 后转为半开
+	CircuitOpen
+	// CircuitHalfOpen 放行少量试探请求，全部成功则关闭，任一失败则重新熔断
+	CircuitHalfOpen
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitCounts 为滚动窗口内的统计
+type CircuitCounts struct {
+	Requests            int
+	Failures            int
+	SlowCalls           int
+	ConsecutiveFailures int
+}
+
+type circuitBucket struct {
+	index     int64
+	requests  int
+	failures  int
+	slowCalls int
+}
+
+// CircuitBreaker 熔断器：依赖持续失败时快速拒绝请求，避免雪上加霜，一段时间后放行试探请求探测是否恢复。
+// 触发条件可组合，任一满足即熔断：连续失败次数、滚动窗口内的失败率、滚动窗口内的慢调用率。
+type CircuitBreaker struct {
+	mutex sync.Mutex
+	name  string
+
+	consecutiveFailures int
+	failureRate         float64
+	slowCallRate        float64
+	slowCallDuration    time.Duration
+	minRequests         int
+	window              time.Duration
+	buckets             []circuitBucket
+	openTimeout         time.Duration
+	halfOpenMaxCalls    int
+	isFailure           func(error) bool
+	onStateChanges      []func(name string, from, to CircuitState)
+
+	state             CircuitState
+	generation        uint64
+	consecutive       int
+	openedAt          time.Time
+	halfOpenCalls     int
+	halfOpenSuccesses int
+}
+
+// NewCircuitBreaker 创建熔断器，默认连续失败 5 次熔断，熔断 30 秒后半开，半开时放行 1 个试探请求
+func NewCircuitBreaker(name string) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:                name,
+		consecutiveFailures: 5,
+		minRequests:         10,
+		window:              time.Minute,
+		buckets:             make([]circuitBucket, 10),
+		openTimeout:         30 * time.Second,
+		halfOpenMaxCalls:    1,
+	}
+}
+
+// WithConsecutiveFailures 连续失败 n 次时熔断，n <= 0 表示不按连续失败熔断
+func (breaker *CircuitBreaker) WithConsecutiveFailures(n int) *CircuitBreaker {
+	breaker.consecutiveFailures = n
+	return breaker
+}
+
+// WithFailureRate 滚动窗口内请求数不少于 minRequests 且失败率不低于 rate 时熔断，rate <= 0 表示不启用
+func (breaker *CircuitBreaker) WithFailureRate(rate float64, minRequests int) *CircuitBreaker {
+	breaker.failureRate = rate
+	breaker.minRequests = minRequests
+	return breaker
+}
+
+// WithSlowCallRate 耗时不短于 duration 的调用视为慢调用，滚动窗口内慢调用率不低于 rate 时熔断，rate <= 0 表示不启用；
+// 请求数门槛与 WithFailureRate 共用
+func (breaker *CircuitBreaker) WithSlowCallRate(rate float64, duration time.Duration) *CircuitBreaker {
+	breaker.slowCallRate = rate
+	breaker.slowCallDuration = duration
+	return breaker
+}
+
+// WithWindow 设置滚动窗口长度与分桶个数
+func (breaker *CircuitBreaker) WithWindow(window time.Duration, buckets int) *CircuitBreaker {
+	breaker.window = window
+	breaker.buckets = make([]circuitBucket, max(buckets, 1))
+	return breaker
+}
+
+// WithOpenTimeout 设置熔断后转为半开前的等待时间
+func (breaker *CircuitBreaker) WithOpenTimeout(timeout time.Duration) *CircuitBreaker {
+	breaker.openTimeout = timeout
+	return breaker
+}
+
+// WithHalfOpenMaxCalls 设置半开时放行的试探请求个数，全部成功才关闭
+func (breaker *CircuitBreaker) WithHalfOpenMaxCalls(n int) *CircuitBreaker {
+	breaker.halfOpenMaxCalls = max(n, 1)
+	return breaker
+}
+
+// WithFailureClassifier 设置哪些错误计为失败，默认非 nil 错误均为失败
+func (breaker *CircuitBreaker) WithFailureClassifier(isFailure func(error) bool) *CircuitBreaker {
+	breaker.isFailure = isFailure
+	return breaker
+}
+
+// WithStateChangeCallback 添加状态变化回调，回调在锁外同步调用
+func (breaker *CircuitBreaker) WithStateChangeCallback(callback func(name string, from, to CircuitState)) *CircuitBreaker {
+	breaker.onStateChanges = append(breaker.onStateChanges, callback)
+	return breaker
+}
+
+func (breaker *CircuitBreaker) Name() string {
+	return breaker.name
+}
+
+// State 返回当前状态，熔断已超过 openTimeout 时返回半开
+func (breaker *CircuitBreaker) State() CircuitState {
+	breaker.mutex.Lock()
+	notify := breaker.refreshState(time.Now())
+	state := breaker.state
+	breaker.mutex.Unlock()
+
+	notify()
+	return state
+}
+
+// Counts 返回滚动窗口内的统计
+func (breaker *CircuitBreaker) Counts() CircuitCounts {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	counts := breaker.windowCounts(time.Now())
+	counts.ConsecutiveFailures = breaker.consecutive
+	return counts
+}
+
+// Reset 恢复为关闭状态并清空统计
+func (breaker *CircuitBreaker) Reset() {
+	breaker.mutex.Lock()
+	notify := breaker.setState(CircuitClosed, time.Now())
+	breaker.mutex.Unlock()
+
+	notify()
+}
+
+// Allow 申请执行一次调用，被拒绝时返回 ErrCircuitOpen 或 ErrCircuitTooManyRequests；
+// 否则调用方执行完毕后须调用 done 报告结果
+func (breaker *CircuitBreaker) Allow() (done func(err error), err error) {
+	now := time.Now()
+
+	breaker.mutex.Lock()
+	notify := breaker.refreshState(now)
+
+	switch breaker.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if breaker.halfOpenCalls >= breaker.halfOpenMaxCalls {
+			err = ErrCircuitTooManyRequests
+		} else {
+			breaker.halfOpenCalls++
+		}
+	}
+
+	generation := breaker.generation
+	breaker.mutex.Unlock()
+
+	notify()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			breaker.report(generation, err, time.Since(now))
+		})
+	}, nil
+}
+
+// Execute 经熔断器执行 fn，被拒绝时不执行；fn panic 时计为失败并以 basic.PanicError 返回
+func (breaker *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	done, err := breaker.Allow()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		done(err)
+	}()
+
+	defer basic.RecoverPanic(&err)
+
+	return fn(ctx)
+}
+
+func (breaker *CircuitBreaker) failed(err error) bool {
+	if breaker.isFailure != nil {
+		return breaker.isFailure(err)
+	}
+	return err != nil
+}
+
+func (breaker *CircuitBreaker) report(generation uint64, err error, duration time.Duration) {
+	now := time.Now()
+	failed := breaker.failed(err)
+	slow := breaker.slowCallDuration > 0 && duration >= breaker.slowCallDuration
+
+	breaker.mutex.Lock()
+	notify := func() {}
+
+	// 状态已变化，结果属于上一轮，忽略
+	if generation == breaker.generation {
+		switch breaker.state {
+		case CircuitClosed:
+			bucket := breaker.bucket(now)
+			bucket.requests++
+			if failed {
+				bucket.failures++
+				breaker.consecutive++
+			} else {
+				breaker.consecutive = 0
+			}
+			if slow {
+				bucket.slowCalls++
+			}
+
+			if breaker.shouldTrip(now) {
+				notify = breaker.setState(CircuitOpen, now)
+			}
+		case CircuitHalfOpen:
+			if failed || slow && breaker.slowCallRate > 0 {
+				notify = breaker.setState(CircuitOpen, now)
+			} else if breaker.halfOpenSuccesses++; breaker.halfOpenSuccesses >= breaker.halfOpenMaxCalls {
+				notify = breaker.setState(CircuitClosed, now)
+			}
+		}
+	}
+
+	breaker.mutex.Unlock()
+	notify()
+}
+
+func (breaker *CircuitBreaker) shouldTrip(now time.Time) bool {
+	if breaker.consecutiveFailures > 0 && breaker.consecutive >= breaker.consecutiveFailures {
+		return true
+	}
+
+	counts := breaker.windowCounts(now)
+	if counts.Requests == 0 || counts.Requests < breaker.minRequests {
+		return false
+	}
+
+	requests := float64(counts.Requests)
+	if breaker.failureRate > 0 && float64(counts.Failures)/requests >= breaker.failureRate {
+		return true
+	}
+
+	return breaker.slowCallRate > 0 && float64(counts.SlowCalls)/requests >= breaker.slowCallRate
+}
+
+func (breaker *CircuitBreaker) bucketIndex(now time.Time) int64 {
+	width := max(breaker.window/time.Duration(len(breaker.buckets)), 1)
+	return now.UnixNano() / int64(width)
+}
+
+// bucket 返回当前时间所在的桶，复用前清空过期的数据
+func (breaker *CircuitBreaker) bucket(now time.Time) *circuitBucket {
+	index := breaker.bucketIndex(now)
+	bucket := &breaker.buckets[index%int64(len(breaker.buckets))]
+	if bucket.index != index {
+		*bucket = circuitBucket{index: index}
+	}
+	return bucket
+}
+
+func (breaker *CircuitBreaker) windowCounts(now time.Time) (counts CircuitCounts) {
+	current := breaker.bucketIndex(now)
+	for _, bucket := range breaker.buckets {
+		if current-bucket.index < int64(len(breaker.buckets)) {
+			counts.Requests += bucket.requests
+			counts.Failures += bucket.failures
+			counts.SlowCalls += bucket.slowCalls
+		}
+	}
+	return
+}
+
+// refreshState 熔断超时后转为半开，调用方须持有锁，并在释放锁后调用返回的通知函数
+func (breaker *CircuitBreaker) refreshState(now time.Time) func() {
+	if breaker.state == CircuitOpen && now.Sub(breaker.openedAt) >= breaker.openTimeout {
+		return breaker.setState(CircuitHalfOpen, now)
+	}
+	return func() {}
+}
+
+func (breaker *CircuitBreaker) setState(state CircuitState, now time.Time) func() {
+	from := breaker.state
+
+	breaker.state = state
+	breaker.generation++
+	breaker.halfOpenCalls, breaker.halfOpenSuccesses = 0, 0
+
+	switch state {
+	case CircuitOpen:
+		breaker.openedAt = now
+	case CircuitClosed:
+		breaker.consecutive = 0
+		clear(breaker.buckets)
+	}
+
+	if from == state {
+		return func() {}
+	}
+
+	callbacks := breaker.onStateChanges
+	return func() {
+		for _, callback := range callbacks {
+			callback(breaker.name, from, state)
+		}
+	}
+}
+
+// CircuitBreakerRegistry 按键管理熔断器，如每个下游主机一个
+type CircuitBreakerRegistry[Key comparable] struct {
+	mutex    sync.Mutex
+	breakers map[Key]*CircuitBreaker
+	factory  func(Key) *CircuitBreaker
+}
+
+// NewCircuitBreakerRegistry 创建注册表，熔断器在首次使用时由 factory 创建
+func NewCircuitBreakerRegistry[Key comparable](factory func(Key) *CircuitBreaker) *CircuitBreakerRegistry[Key] {
+	return &CircuitBreakerRegistry[Key]{
+		breakers: map[Key]*CircuitBreaker{},
+		factory:  factory,
+	}
+}
+
+func (registry *CircuitBreakerRegistry[Key]) Get(key Key) *CircuitBreaker {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	breaker, ok := registry.breakers[key]
+	if !ok {
+		breaker = registry.factory(key)
+		registry.breakers[key] = breaker
+	}
+
+	return breaker
+}
+
+func (registry *CircuitBreakerRegistry[Key]) Execute(ctx context.Context, key Key, fn func(ctx context.Context) error) error {
+	return registry.Get(key).Execute(ctx, fn)
+}
+
+func (registry *CircuitBreakerRegistry[Key]) Remove(key Key) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	delete(registry.breakers, key)
+}
+
+// States 返回全部熔断器的当前状态
+func (registry *CircuitBreakerRegistry[Key]) States() map[Key]CircuitState {
+	registry.mutex.Lock()
+	breakers := make(map[Key]*CircuitBreaker, len(registry.breakers))
+	for key, breaker := range registry.breakers {
+		breakers[key] = breaker
+	}
+	registry.mutex.Unlock()
+
+	states := make(map[Key]CircuitState, len(breakers))
+	for key, breaker := range breakers {
+		states[key] = breaker.State()
+	}
+	return states
+}
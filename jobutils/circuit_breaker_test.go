@@ -0,0 +1,198 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestUpstream = errors.New("upstream failed")
+
+func failCircuit(breaker *CircuitBreaker, n int) {
+	for i := 0; i < n; i++ {
+		breaker.Execute(nil, func(ctx context.Context) error {
+			return errTestUpstream
+		})
+	}
+}
+
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+	var mutex sync.Mutex
+	var transitions []string
+
+	breaker := NewCircuitBreaker("upstream").
+		WithConsecutiveFailures(3).
+		WithOpenTimeout(20 * time.Millisecond).
+		WithHalfOpenMaxCalls(2).
+		WithStateChangeCallback(func(name string, from, to CircuitState) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			transitions = append(transitions, name+":"+from.String()+"->"+to.String())
+		})
+
+	failCircuit(breaker, 2)
+	assert.Nil(t, breaker.Execute(nil, func(ctx context.Context) error { return nil }))
+	failCircuit(breaker, 2)
+	assert.Equal(t, CircuitClosed, breaker.State())
+	assert.Equal(t, 2, breaker.Counts().ConsecutiveFailures)
+
+	failCircuit(breaker, 1)
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	called := false
+	err := breaker.Execute(nil, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called)
+
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+
+	done1, err := breaker.Allow()
+	assert.Nil(t, err)
+	done2, err := breaker.Allow()
+	assert.Nil(t, err)
+	_, err = breaker.Allow()
+	assert.ErrorIs(t, err, ErrCircuitTooManyRequests)
+
+	done1(nil)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	done2(nil)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	assert.Equal(t, []string{
+		"upstream:closed->open",
+		"upstream:open->half-open",
+		"upstream:half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	breaker := NewCircuitBreaker("upstream").
+		WithConsecutiveFailures(1).
+		WithOpenTimeout(10 * time.Millisecond)
+
+	failCircuit(breaker, 1)
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	time.Sleep(15 * time.Millisecond)
+	failCircuit(breaker, 1)
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	// 上一轮的结果不影响新状态
+	breaker.Reset()
+	done, err := breaker.Allow()
+	assert.Nil(t, err)
+	failCircuit(breaker, 1)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	done(nil)
+	done(nil)
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func TestCircuitBreakerFailureRate(t *testing.T) {
+	breaker := NewCircuitBreaker("upstream").
+		WithConsecutiveFailures(0).
+		WithFailureRate(0.5, 10).
+		WithWindow(time.Minute, 6)
+
+	ok := func(ctx context.Context) error { return nil }
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, breaker.Execute(nil, ok))
+		failCircuit(breaker, 1)
+		if i < 4 {
+			assert.Equal(t, CircuitClosed, breaker.State(), "round #%d", i)
+		}
+	}
+
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	breaker.Reset()
+	assert.Equal(t, CircuitCounts{}, breaker.Counts())
+}
+
+func TestCircuitBreakerWindowExpires(t *testing.T) {
+	breaker := NewCircuitBreaker("upstream").
+		WithConsecutiveFailures(0).
+		WithFailureRate(0.5, 4).
+		WithWindow(40*time.Millisecond, 4)
+
+	failCircuit(breaker, 3)
+	assert.Equal(t, 3, breaker.Counts().Failures)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, 0, breaker.Counts().Requests)
+
+	failCircuit(breaker, 1)
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerSlowCalls(t *testing.T) {
+	breaker := NewCircuitBreaker("upstream").
+		WithFailureRate(0, 2).
+		WithSlowCallRate(0.5, 5*time.Millisecond)
+
+	slow := func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	assert.Nil(t, breaker.Execute(nil, slow))
+	assert.Equal(t, CircuitClosed, breaker.State())
+	assert.Nil(t, breaker.Execute(nil, slow))
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.Equal(t, 0, breaker.Counts().Failures)
+}
+
+func TestCircuitBreakerClassifierAndPanic(t *testing.T) {
+	breaker := NewCircuitBreaker("upstream").
+		WithConsecutiveFailures(2).
+		WithFailureClassifier(func(err error) bool {
+			return err != nil && !errors.Is(err, context.Canceled)
+		})
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, breaker.Execute(nil, func(ctx context.Context) error {
+			return context.Canceled
+		}), context.Canceled)
+	}
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	err := breaker.Execute(nil, func(ctx context.Context) error {
+		panic("boom")
+	})
+	var panicError *basic.PanicError
+	assert.ErrorAs(t, err, &panicError)
+	assert.Equal(t, 1, breaker.Counts().ConsecutiveFailures)
+}
+
+func TestCircuitBreakerRegistry(t *testing.T) {
+	registry := NewCircuitBreakerRegistry(func(host string) *CircuitBreaker {
+		return NewCircuitBreaker(host).WithConsecutiveFailures(1)
+	})
+
+	assert.Same(t, registry.Get("a"), registry.Get("a"))
+	assert.Equal(t, "b", registry.Get("b").Name())
+
+	assert.ErrorIs(t, registry.Execute(nil, "a", func(ctx context.Context) error {
+		return errTestUpstream
+	}), errTestUpstream)
+	assert.ErrorIs(t, registry.Execute(nil, "a", func(ctx context.Context) error {
+		return nil
+	}), ErrCircuitOpen)
+
+	assert.Equal(t, map[string]CircuitState{
+		"a": CircuitOpen,
+		"b": CircuitClosed,
+	}, registry.States())
+
+	registry.Remove("a")
+	assert.Equal(t, CircuitClosed, registry.Get("a").State())
+}
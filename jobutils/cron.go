@@ -0,0 +1,367 @@
+package jobutils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fasionchan/goutils/baseutils"
+)
+
+var ErrCronSyntax = errors.New("jobutils: invalid cron expression")
+
+// Schedule 计算下一次执行时间，返回零值表示不再执行
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// NextTimes 预览 after 之后的 n 次执行时间
+func NextTimes(schedule Schedule, after time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		after = schedule.Next(after)
+		if after.IsZero() {
+			break
+		}
+		times = append(times, after)
+	}
+	return times
+}
+
+type cronBounds struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronSeconds = cronBounds{min: 0, max: 59}
+	cronMinutes = cronBounds{min: 0, max: 59}
+	cronHours   = cronBounds{min: 0, max: 23}
+	cronDays    = cronBounds{min: 1, max: 31}
+	cronMonths  = cronBounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronWeekdays = cronBounds{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+func (bounds cronBounds) parse(s string) (int, error) {
+	if value, ok := bounds.names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// parseCronField 解析单个字段，支持 *、?、a-b、*/n、a-b/n、a/n 以及逗号分隔的列表
+func parseCronField(field string, bounds cronBounds) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: bad step %q", ErrCronSyntax, part)
+			}
+		}
+
+		low, high := bounds.min, bounds.max
+		if rangePart != "*" && rangePart != "?" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			if low, err = bounds.parse(lowPart); err != nil {
+				return 0, fmt.Errorf("%w: bad value %q", ErrCronSyntax, part)
+			}
+
+			if isRange {
+				if high, err = bounds.parse(highPart); err != nil {
+					return 0, fmt.Errorf("%w: bad value %q", ErrCronSyntax, part)
+				}
+			} else if !hasStep {
+				high = low
+			}
+		}
+
+		if low < bounds.min || high > bounds.max || low > high {
+			return 0, fmt.Errorf("%w: %q out of range %d-%d", ErrCronSyntax, part, bounds.min, bounds.max)
+		}
+
+		for i := low; i <= high; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+// CronSchedule 为 cron 表达式描述的日程
+type CronSchedule struct {
+	spec     string
+	second   uint64
+	minute   uint64
+	hour     uint64
+	day      uint64
+	month    uint64
+	weekday  uint64
+	dayStar  bool
+	location *time.Location
+}
+
+// ParseCron 解析 cron 表达式，时区为 time.Local，详见 ParseCronIn
+func ParseCron(spec string) (*CronSchedule, error) {
+	return ParseCronIn(spec, time.Local)
+}
+
+// ParseCronIn 解析 cron 表达式，按 location 时区计算：
+//   - 5 个字段为 分 时 日 月 周，6 个字段在最前面加上秒
+//   - 月与周可用英文缩写，周的 0 与 7 均为周日
+//   - 日与周均有限制时，满足其一即可（与标准 cron 一致）
+//   - 支持 @yearly、@monthly、@weekly、@daily、@hourly 等简写
+//   - 可用 CRON_TZ=Asia/Shanghai 或 TZ=Asia/Shanghai 前缀指定时区
+func ParseCronIn(spec string, location *time.Location) (*CronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		zone, rest, _ := strings.Cut(expr, " ")
+		_, name, _ := strings.Cut(zone, "=")
+
+		var err error
+		if location, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCronSyntax, err)
+		}
+		expr = strings.TrimSpace(rest)
+	}
+
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields, got %d in %q", ErrCronSyntax, len(fields), spec)
+	}
+
+	if location == nil {
+		location = time.Local
+	}
+
+	schedule := &CronSchedule{spec: spec, location: location}
+
+	var err error
+	for i, target := range []struct {
+		bits   *uint64
+		bounds cronBounds
+	}{
+		{&schedule.second, cronSeconds},
+		{&schedule.minute, cronMinutes},
+		{&schedule.hour, cronHours},
+		{&schedule.day, cronDays},
+		{&schedule.month, cronMonths},
+		{&schedule.weekday, cronWeekdays},
+	} {
+		if *target.bits, err = parseCronField(fields[i], target.bounds); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7 也表示周日
+	if schedule.weekday&(1<<7) != 0 {
+		schedule.weekday = schedule.weekday&^(1<<7) | 1
+	}
+
+	schedule.dayStar = strings.HasPrefix(fields[3], "*") || strings.HasPrefix(fields[3], "?") ||
+		strings.HasPrefix(fields[5], "*") || strings.HasPrefix(fields[5], "?")
+
+	return schedule, nil
+}
+
+func MustParseCron(spec string) *CronSchedule {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (schedule *CronSchedule) String() string {
+	return schedule.spec
+}
+
+func (schedule *CronSchedule) Location() *time.Location {
+	return schedule.location
+}
+
+func (schedule *CronSchedule) dayMatches(t time.Time) bool {
+	day := schedule.day&(1<<t.Day()) != 0
+	weekday := schedule.weekday&(1<<t.Weekday()) != 0
+	if schedule.dayStar {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// Next 返回 after 之后的第一个匹配时间，5 年内没有匹配时返回零值
+func (schedule *CronSchedule) Next(after time.Time) time.Time {
+	location := schedule.location
+	t := after.In(location)
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+	// 从大到小逐个字段推进，进位后从头检查
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for schedule.month&(1<<t.Month()) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !schedule.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for schedule.hour&(1<<t.Hour()) == 0 {
+		// 夏令时跳过的整点可能被规整回当前小时，须保证前进
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		if !next.After(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, location).Add(time.Hour)
+		}
+
+		t = next
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for schedule.minute&(1<<t.Minute()) == 0 {
+		t = skipRepeatedHour(t, t.Truncate(time.Minute).Add(time.Minute))
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for schedule.second&(1<<t.Second()) == 0 {
+		t = skipRepeatedHour(t, t.Add(time.Second))
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// skipRepeatedHour 夏令时结束时同一小时会出现两次，只按第一次计算，避免重复执行
+func skipRepeatedHour(prev, next time.Time) time.Time {
+	if next.Hour() == prev.Hour() && next.Minute() < prev.Minute() {
+		return next.Add(time.Hour)
+	}
+	return next
+}
+
+// EverySchedule 每隔固定时间执行一次，对应 @every 表达式
+type EverySchedule time.Duration
+
+func NewEverySchedule(interval time.Duration) EverySchedule {
+	return EverySchedule(interval)
+}
+
+func (schedule EverySchedule) Next(after time.Time) time.Time {
+	if schedule <= 0 {
+		return time.Time{}
+	}
+	return after.Add(time.Duration(schedule))
+}
+
+func (schedule EverySchedule) String() string {
+	return "@every " + time.Duration(schedule).String()
+}
+
+// ParseSchedule 解析 @every 间隔（如 @every 1m30s）或 cron 表达式（见 ParseCronIn）
+func ParseSchedule(spec string) (Schedule, error) {
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(spec), "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w: bad interval in %q", ErrCronSyntax, spec)
+		}
+		return EverySchedule(interval), nil
+	}
+
+	return ParseCron(spec)
+}
+
+// DailySchedule 每天（或指定的星期几）在若干日内时间点执行，如工作日 09:30
+type DailySchedule struct {
+	times    baseutils.IntraDayTimes
+	weekdays uint8
+	location *time.Location
+}
+
+// NewDailySchedule 创建每天在 times 执行的日程，location 为 nil 时使用 time.Local
+func NewDailySchedule(location *time.Location, times ...baseutils.IntraDayTime) *DailySchedule {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &DailySchedule{
+		times:    baseutils.IntraDayTimes(slices.Clone(times)).SortAsc(),
+		location: location,
+	}
+}
+
+// WithWeekdays 限定星期几执行，不指定则每天执行
+func (schedule *DailySchedule) WithWeekdays(weekdays ...time.Weekday) *DailySchedule {
+	schedule.weekdays = 0
+	for _, weekday := range weekdays {
+		schedule.weekdays |= 1 << weekday
+	}
+	return schedule
+}
+
+func (schedule *DailySchedule) Next(after time.Time) time.Time {
+	if schedule.times.Empty() {
+		return time.Time{}
+	}
+
+	t := after.In(schedule.location)
+	for i := 0; i <= 7; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, schedule.location)
+		if schedule.weekdays != 0 && schedule.weekdays&(1<<day.Weekday()) == 0 {
+			continue
+		}
+
+		for _, intraDayTime := range schedule.times {
+			hours, minutes, seconds, nanoseconds := intraDayTime.Parts()
+			next := time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, seconds, nanoseconds, schedule.location)
+			if next.After(after) {
+				return next
+			}
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,156 @@
+package jobutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/baseutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skip(err)
+	}
+	return location
+}
+
+func TestParseCron(t *testing.T) {
+	for _, spec := range []string{
+		"* * * * *",
+		"*/15 9-17 * * MON-FRI",
+		"0 30 9 * * 1-5",
+		"0 0 1,15 * *",
+		"0 0 * JAN,jul 7",
+		"CRON_TZ=Asia/Shanghai 30 9 * * 1-5",
+		"@daily",
+		"@Hourly",
+	} {
+		_, err := ParseCron(spec)
+		assert.Nil(t, err, spec)
+	}
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"TZ=Nowhere/Unknown * * * * *",
+	} {
+		_, err := ParseCron(spec)
+		assert.ErrorIs(t, err, ErrCronSyntax, spec)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	utc := time.UTC
+	at := func(s string) time.Time {
+		result, err := time.ParseInLocation(time.DateTime, s, utc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	cases := []struct {
+		spec  string
+		after string
+		next  []string
+	}{
+		{"*/15 * * * * *", "2024-01-01 00:00:00", []string{"2024-01-01 00:00:15", "2024-01-01 00:00:30"}},
+		{"30 9 * * 1-5", "2024-06-07 09:30:00", []string{"2024-06-10 09:30:00", "2024-06-11 09:30:00"}},
+		{"0 0 29 2 *", "2024-03-01 00:00:00", []string{"2028-02-29 00:00:00"}},
+		{"0 0 31 * *", "2024-04-01 00:00:00", []string{"2024-05-31 00:00:00", "2024-07-31 00:00:00"}},
+		// 日与周均有限制时满足其一即可
+		{"0 0 13 * 5", "2024-09-01 00:00:00", []string{"2024-09-06 00:00:00", "2024-09-13 00:00:00", "2024-09-20 00:00:00"}},
+		{"0 12 * * 7", "2024-09-01 12:00:00", []string{"2024-09-08 12:00:00"}},
+		{"@monthly", "2024-12-15 08:00:00", []string{"2025-01-01 00:00:00"}},
+	}
+
+	for _, c := range cases {
+		schedule, err := ParseCronIn(c.spec, utc)
+		if !assert.Nil(t, err, c.spec) {
+			continue
+		}
+
+		var expected []time.Time
+		for _, next := range c.next {
+			expected = append(expected, at(next))
+		}
+		assert.Equal(t, expected, NextTimes(schedule, at(c.after), len(c.next)), c.spec)
+	}
+
+	assert.True(t, MustParseCron("0 0 30 2 *").Next(time.Now()).IsZero())
+}
+
+func TestCronScheduleTimeZone(t *testing.T) {
+	shanghai := mustLoadLocation(t, "Asia/Shanghai")
+
+	schedule, err := ParseCron("TZ=Asia/Shanghai 30 9 * * 1-5")
+	assert.Nil(t, err)
+	assert.Equal(t, shanghai.String(), schedule.Location().String())
+
+	// 周五 UTC 02:00 即上海 10:00，下一次为周一上海 09:30
+	next := schedule.Next(time.Date(2024, 6, 7, 2, 0, 0, 0, time.UTC))
+	assert.True(t, next.Equal(time.Date(2024, 6, 10, 1, 30, 0, 0, time.UTC)), next)
+}
+
+func TestCronScheduleDaylightSaving(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	// 2024-03-10 02:30 不存在，当天跳过
+	schedule, err := ParseCronIn("30 2 * * *", newYork)
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, newYork))
+	assert.Equal(t, time.Date(2024, 3, 11, 2, 30, 0, 0, newYork), next)
+
+	// 2024-11-03 01:30 出现两次，只执行一次
+	schedule, err = ParseCronIn("30 1 * * *", newYork)
+	assert.Nil(t, err)
+	times := NextTimes(schedule, time.Date(2024, 11, 2, 12, 0, 0, 0, newYork), 2)
+	assert.Equal(t, 3, times[0].Day())
+	assert.Equal(t, 4, times[1].Day())
+}
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("@every 1m30s")
+	assert.Nil(t, err)
+	assert.Equal(t, NewEverySchedule(90*time.Second), schedule)
+
+	now := time.Now()
+	assert.Equal(t, now.Add(90*time.Second), schedule.Next(now))
+
+	_, err = ParseSchedule("@every -1s")
+	assert.ErrorIs(t, err, ErrCronSyntax)
+
+	schedule, err = ParseSchedule("0 * * * *")
+	assert.Nil(t, err)
+	assert.IsType(t, &CronSchedule{}, schedule)
+}
+
+func TestDailySchedule(t *testing.T) {
+	shanghai := mustLoadLocation(t, "Asia/Shanghai")
+
+	schedule := NewDailySchedule(shanghai,
+		baseutils.MustParseIntraDayTime("15:00:00"),
+		baseutils.MustParseIntraDayTime("09:30:00"),
+	).WithWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+	// 2024-06-07 为周五
+	times := NextTimes(schedule, time.Date(2024, 6, 7, 10, 0, 0, 0, shanghai), 3)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 6, 7, 15, 0, 0, 0, shanghai),
+		time.Date(2024, 6, 10, 9, 30, 0, 0, shanghai),
+		time.Date(2024, 6, 10, 15, 0, 0, 0, shanghai),
+	}, times)
+
+	assert.True(t, NewDailySchedule(nil).Next(time.Now()).IsZero())
+}
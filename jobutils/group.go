@@ -140,6 +140,7 @@ func (group *JobGroup) Wait() {
 	group.wg.Wait()
 }
 
+// Deprecated: 后台协程无法停止，令牌满后会永久阻塞；请使用 TokenBucket 等 RateLimiter。
 type TokenGenerator struct {
 	Tokens chan struct{}
 
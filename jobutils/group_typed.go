@@ -0,0 +1,160 @@
+package jobutils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/fasionchan/goutils/stl"
+)
+
+// TypedJobGroup 为带结果的 JobGroup，用法与 errgroup 类似：
+// Go 提交的任务共享同一个 ctx，Wait 按提交顺序返回结果；
+// 默认第一个错误取消 ctx 并由 Wait 返回，WithCollectAll 时不取消，Wait 返回全部错误。
+// 任务 panic 时转为 *basic.PanicError，含调用栈
+type TypedJobGroup[T any] struct {
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	tokens     JobTokens
+	timeout    time.Duration
+	collectAll bool
+
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+	results  []T
+	errs     stl.Errors
+	firstErr error
+}
+
+// NewTypedJobGroup 创建任务组，ctx 为 nil 时使用 context.Background()
+func NewTypedJobGroup[T any](ctx context.Context) *TypedJobGroup[T] {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	group := &TypedJobGroup[T]{}
+	group.ctx, group.cancel = context.WithCancelCause(ctx)
+	return group
+}
+
+// WithLimit 限制同时执行的任务数，达到上限时 Go 阻塞；n < 0 表示不限制
+func (group *TypedJobGroup[T]) WithLimit(n int) *TypedJobGroup[T] {
+	if n == 0 {
+		n = 1
+	}
+	group.tokens = NewJobTokens(n)
+	return group
+}
+
+// WithTimeout 设置每个任务的默认超时，任务须响应 ctx 才能及时结束
+func (group *TypedJobGroup[T]) WithTimeout(timeout time.Duration) *TypedJobGroup[T] {
+	group.timeout = timeout
+	return group
+}
+
+// WithCollectAll 出错时不取消其他任务，Wait 返回按提交顺序排列的全部错误
+func (group *TypedJobGroup[T]) WithCollectAll(collectAll bool) *TypedJobGroup[T] {
+	group.collectAll = collectAll
+	return group
+}
+
+// Context 返回任务共享的 ctx
+func (group *TypedJobGroup[T]) Context() context.Context {
+	return group.ctx
+}
+
+// Go 提交任务，返回其在结果中的序号
+func (group *TypedJobGroup[T]) Go(fn func(ctx context.Context) (T, error)) int {
+	return group.GoWithTimeout(group.timeout, fn)
+}
+
+// GoWithTimeout 提交任务并指定超时，timeout <= 0 表示不超时
+func (group *TypedJobGroup[T]) GoWithTimeout(timeout time.Duration, fn func(ctx context.Context) (T, error)) int {
+	group.mutex.Lock()
+	index := len(group.results)
+	var zero T
+	group.results = append(group.results, zero)
+	group.errs = append(group.errs, nil)
+	group.mutex.Unlock()
+
+	group.wg.Add(1)
+
+	// 已取消（如已有任务出错）时不再执行
+	if !group.tokens.Acquire(group.ctx, -1) {
+		group.finish(index, zero, context.Cause(group.ctx))
+		return index
+	}
+
+	// ctx 结束与令牌可用同时发生时也可能取得令牌
+	if group.ctx.Err() != nil {
+		group.tokens.Release()
+		group.finish(index, zero, context.Cause(group.ctx))
+		return index
+	}
+
+	go group.run(index, timeout, fn)
+
+	return index
+}
+
+func (group *TypedJobGroup[T]) run(index int, timeout time.Duration, fn func(ctx context.Context) (T, error)) {
+	defer group.tokens.Release()
+
+	ctx := group.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var result T
+	var err error
+	func() {
+		defer basic.RecoverPanic(&err)
+		result, err = fn(ctx)
+	}()
+
+	group.finish(index, result, err)
+}
+
+func (group *TypedJobGroup[T]) finish(index int, result T, err error) {
+	defer group.wg.Done()
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	group.results[index] = result
+	group.errs[index] = err
+
+	if err != nil && group.firstErr == nil {
+		group.firstErr = err
+		if !group.collectAll {
+			group.cancel(err)
+		}
+	}
+}
+
+// Wait 等待全部任务结束，返回按提交顺序排列的结果（出错的任务为零值）；
+// 默认返回第一个错误，WithCollectAll 时返回 stl.Errors
+func (group *TypedJobGroup[T]) Wait() ([]T, error) {
+	group.wg.Wait()
+	group.cancel(nil)
+
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	results := append([]T(nil), group.results...)
+	if group.collectAll {
+		return results, group.errs.Simplify()
+	}
+	return results, group.firstErr
+}
+
+// Errors 返回按提交顺序排列的各任务错误，须在 Wait 后调用
+func (group *TypedJobGroup[T]) Errors() stl.Errors {
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	return append(stl.Errors(nil), group.errs...)
+}
@@ -0,0 +1,152 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedJobGroupResults(t *testing.T) {
+	group := NewTypedJobGroup[int](nil).WithLimit(2)
+
+	var running, maxRunning atomic.Int32
+	for i := 0; i < 6; i++ {
+		index := group.Go(func(ctx context.Context) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+
+			// 后提交的先完成
+			time.Sleep(time.Duration(6-i) * 2 * time.Millisecond)
+			return i * i, nil
+		})
+		assert.Equal(t, i, index)
+	}
+
+	results, err := group.Wait()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 25}, results)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	assert.ErrorIs(t, context.Cause(group.Context()), context.Canceled)
+}
+
+func TestTypedJobGroupFailFast(t *testing.T) {
+	errFirst := errors.New("first")
+
+	group := NewTypedJobGroup[string](context.Background())
+
+	group.Go(func(ctx context.Context) (string, error) {
+		return "", errFirst
+	})
+	group.Go(func(ctx context.Context) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "canceled", context.Cause(ctx)
+		case <-time.After(time.Second):
+			return "finished", nil
+		}
+	})
+
+	time.Sleep(5 * time.Millisecond)
+	var ran atomic.Bool
+	group.Go(func(ctx context.Context) (string, error) {
+		ran.Store(true)
+		return "late", nil
+	})
+
+	start := time.Now()
+	results, err := group.Wait()
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, errFirst, err)
+	assert.Equal(t, []string{"", "canceled", ""}, results)
+	assert.False(t, ran.Load())
+
+	errs := group.Errors()
+	assert.Len(t, errs, 3)
+	assert.ErrorIs(t, errs[1], errFirst)
+	assert.ErrorIs(t, errs[2], errFirst)
+}
+
+func TestTypedJobGroupCollectAll(t *testing.T) {
+	errA := errors.New("a")
+	errC := errors.New("c")
+
+	group := NewTypedJobGroup[string](nil).WithCollectAll(true)
+	for _, name := range []string{"a", "b", "c"} {
+		group.Go(func(ctx context.Context) (string, error) {
+			switch name {
+			case "a":
+				return "", errA
+			case "c":
+				time.Sleep(10 * time.Millisecond)
+				assert.Nil(t, ctx.Err())
+				return "", errC
+			}
+			return name, nil
+		})
+	}
+
+	results, err := group.Wait()
+	assert.Equal(t, []string{"", "b", ""}, results)
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errC)
+
+	errs := group.Errors()
+	assert.Equal(t, errA, errs[0])
+	assert.Nil(t, errs[1])
+	assert.Equal(t, errC, errs[2])
+}
+
+func TestTypedJobGroupPanicAndTimeout(t *testing.T) {
+	group := NewTypedJobGroup[int](nil).WithCollectAll(true).WithTimeout(10 * time.Millisecond)
+
+	group.Go(func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	group.Go(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	group.GoWithTimeout(time.Second, func(ctx context.Context) (int, error) {
+		time.Sleep(20 * time.Millisecond)
+		return 3, ctx.Err()
+	})
+
+	results, err := group.Wait()
+	assert.NotNil(t, err)
+	assert.Equal(t, []int{0, 0, 3}, results)
+
+	errs := group.Errors()
+
+	var panicError *basic.PanicError
+	if assert.ErrorAs(t, errs[0], &panicError) {
+		assert.Equal(t, "boom", panicError.Exception())
+		assert.True(t, strings.Contains(panicError.Stack(), "TestTypedJobGroupPanicAndTimeout"))
+	}
+	assert.ErrorIs(t, errs[1], context.DeadlineExceeded)
+	assert.Nil(t, errs[2])
+}
+
+func TestTypedJobGroupParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	group := NewTypedJobGroup[int](ctx).WithLimit(1)
+	group.Go(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	_, err := group.Wait()
+	assert.ErrorIs(t, err, context.Canceled)
+}
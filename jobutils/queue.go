@@ -0,0 +1,376 @@
+package jobutils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/fasionchan/goutils/stl"
+)
+
+var (
+	ErrJobQueueStopped = errors.New("jobutils: job queue is stopped")
+	// ErrJobPermanent 处理函数返回的错误包装了它时不再重试，直接移入死信
+	ErrJobPermanent = errors.New("jobutils: permanent job failure")
+)
+
+// QueueJob 为 JobQueue 中的一个任务
+type QueueJob struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind,omitempty"`
+	Payload     []byte    `json:"payload,omitempty"`
+	Attempts    int       `json:"attempts"`              // 已领取次数，用于重试计数
+	LeaseID     uint64    `json:"leaseId"`               // 每次领取递增、永不重置，用于校验租约
+	MaxAttempts int       `json:"maxAttempts,omitempty"` // 为 0 时使用队列的设置
+	RunAt       time.Time `json:"runAt"`                 // 可被领取的时间
+	EnqueuedAt  time.Time `json:"enqueuedAt"`
+	FailedAt    time.Time `json:"failedAt,omitzero"` // 首次失败时间
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// NewQueueJob 创建任务，kind 供处理函数区分任务类型
+func NewQueueJob(kind string, payload []byte) *QueueJob {
+	return &QueueJob{
+		Kind:    kind,
+		Payload: payload,
+	}
+}
+
+// WithID 指定任务 ID，用于去重；不指定时入队时随机生成
+func (job *QueueJob) WithID(id string) *QueueJob {
+	job.ID = id
+	return job
+}
+
+// WithDelay 延迟 delay 后执行
+func (job *QueueJob) WithDelay(delay time.Duration) *QueueJob {
+	job.RunAt = time.Now().Add(delay)
+	return job
+}
+
+// WithRunAt 于 runAt 执行
+func (job *QueueJob) WithRunAt(runAt time.Time) *QueueJob {
+	job.RunAt = runAt
+	return job
+}
+
+func (job *QueueJob) WithMaxAttempts(maxAttempts int) *QueueJob {
+	job.MaxAttempts = maxAttempts
+	return job
+}
+
+func newQueueJobID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// JobQueue 为进程内的延迟任务队列：任务保存在 JobStore 中，由有限个 worker 领取执行；
+// 失败后按 Backoff 重试，超过最大次数后移入死信。
+// 领取的任务在可见性超时内未完成时会被再次领取，因此处理函数须幂等，且应在 ctx 结束后尽快返回
+type JobQueue struct {
+	store        JobStore
+	handler      func(ctx context.Context, job QueueJob) error
+	tokens       JobTokens
+	visibility   time.Duration
+	maxAttempts  int
+	backoff      stl.Backoff
+	pollInterval time.Duration
+	onDead       func(job QueueJob)
+	onError      func(job QueueJob, err error)
+
+	mutex   sync.Mutex
+	wake    chan struct{}
+	cancel  context.CancelFunc // 停止领取
+	runCtx  context.Context    // 执行中任务的父 ctx
+	runStop context.CancelFunc
+	started bool
+	stopped bool
+	loop    sync.WaitGroup
+	workers sync.WaitGroup
+}
+
+// NewJobQueue 创建队列，默认 1 个 worker，可见性超时 30 秒，最多执行 3 次，重试间隔从 1 秒指数增长至 1 分钟
+func NewJobQueue(store JobStore, handler func(ctx context.Context, job QueueJob) error) *JobQueue {
+	return &JobQueue{
+		store:        store,
+		handler:      handler,
+		tokens:       NewJobTokens(1),
+		visibility:   30 * time.Second,
+		maxAttempts:  3,
+		backoff:      stl.NewExponentialBackoff(time.Second, time.Minute),
+		pollInterval: time.Second,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// WithWorkers 设置同时执行的任务数
+func (queue *JobQueue) WithWorkers(n int) *JobQueue {
+	queue.tokens = NewJobTokens(max(n, 1))
+	return queue
+}
+
+// WithVisibilityTimeout 设置可见性超时，同时也是处理函数 ctx 的超时
+func (queue *JobQueue) WithVisibilityTimeout(timeout time.Duration) *JobQueue {
+	queue.visibility = timeout
+	return queue
+}
+
+// WithMaxAttempts 设置任务未指定时的最大执行次数
+func (queue *JobQueue) WithMaxAttempts(maxAttempts int) *JobQueue {
+	queue.maxAttempts = max(maxAttempts, 1)
+	return queue
+}
+
+// WithBackoff 设置重试等待策略，elapsed 为自首次失败起已过的时间
+func (queue *JobQueue) WithBackoff(backoff stl.Backoff) *JobQueue {
+	queue.backoff = backoff
+	return queue
+}
+
+// WithPollInterval 设置轮询存储的最长间隔，存储被其他进程修改时可及时发现
+func (queue *JobQueue) WithPollInterval(interval time.Duration) *JobQueue {
+	queue.pollInterval = interval
+	return queue
+}
+
+// WithDeadLetterCallback 设置任务移入死信时的回调
+func (queue *JobQueue) WithDeadLetterCallback(onDead func(job QueueJob)) *JobQueue {
+	queue.onDead = onDead
+	return queue
+}
+
+// WithErrorCallback 设置存储出错时的回调
+func (queue *JobQueue) WithErrorCallback(onError func(job QueueJob, err error)) *JobQueue {
+	queue.onError = onError
+	return queue
+}
+
+func (queue *JobQueue) Store() JobStore {
+	return queue.store
+}
+
+// Enqueue 入队，未指定 ID 时随机生成，未指定 RunAt 时立即执行；返回任务 ID
+func (queue *JobQueue) Enqueue(job *QueueJob) (string, error) {
+	queue.mutex.Lock()
+	stopped := queue.stopped
+	queue.mutex.Unlock()
+
+	if stopped {
+		return "", ErrJobQueueStopped
+	}
+
+	added := *job
+	if added.ID == "" {
+		added.ID = newQueueJobID()
+	}
+
+	added.EnqueuedAt = time.Now()
+	if added.RunAt.IsZero() {
+		added.RunAt = added.EnqueuedAt
+	}
+
+	if err := queue.store.Add(added); err != nil {
+		return "", err
+	}
+
+	queue.notify()
+	return added.ID, nil
+}
+
+// DeadLetters 返回死信
+func (queue *JobQueue) DeadLetters() ([]QueueJob, error) {
+	return queue.store.DeadLetters()
+}
+
+// Revive 将死信重新入队并立即执行
+func (queue *JobQueue) Revive(id string) error {
+	if err := queue.store.Revive(id, time.Now()); err != nil {
+		return err
+	}
+
+	queue.notify()
+	return nil
+}
+
+func (queue *JobQueue) notify() {
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动调度，ctx 结束后不再领取任务，执行中的任务不受影响
+func (queue *JobQueue) Start(ctx context.Context) error {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.stopped {
+		return ErrJobQueueStopped
+	}
+
+	if queue.started {
+		return nil
+	}
+
+	queue.started = true
+
+	// 调度在 Stop 时立即停止，执行中的任务在 Stop 等待超时后才被取消
+	var loopCtx context.Context
+	loopCtx, queue.cancel = context.WithCancel(ctx)
+	queue.runCtx, queue.runStop = context.WithCancel(context.WithoutCancel(ctx))
+
+	queue.loop.Add(1)
+	go queue.dispatch(loopCtx)
+
+	return nil
+}
+
+// Stop 停止领取任务，等待执行中的任务结束；ctx 先结束时取消它们并返回 ctx.Err()，
+// 被取消的任务在可见性超时后会被重新领取
+func (queue *JobQueue) Stop(ctx context.Context) error {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	queue.mutex.Lock()
+	if queue.stopped {
+		queue.mutex.Unlock()
+		return nil
+	}
+
+	queue.stopped = true
+	started := queue.started
+	queue.mutex.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	queue.cancel()
+	queue.loop.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		queue.workers.Wait()
+		close(done)
+	}()
+
+	defer queue.runStop()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (queue *JobQueue) dispatch(ctx context.Context) {
+	defer queue.loop.Done()
+
+	for {
+		if !queue.tokens.Acquire(ctx, -1) {
+			return
+		}
+
+		job, ok, err := queue.store.Lease(time.Now(), queue.visibility)
+		if err != nil {
+			queue.reportError(job, err)
+		}
+
+		if ok {
+			queue.workers.Add(1)
+			go queue.work(job)
+			continue
+		}
+
+		queue.tokens.Release()
+
+		if !queue.idle(ctx) {
+			return
+		}
+	}
+}
+
+// idle 等待至下一个任务可领取、有新任务入队或到达轮询间隔，ctx 结束时返回 false
+func (queue *JobQueue) idle(ctx context.Context) bool {
+	wait := queue.pollInterval
+	if runAt, ok, err := queue.store.NextRunAt(); err == nil && ok {
+		wait = min(wait, time.Until(runAt))
+	}
+
+	timer := time.NewTimer(max(wait, 0))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-queue.wake:
+	case <-timer.C:
+	}
+
+	return true
+}
+
+func (queue *JobQueue) call(ctx context.Context, job QueueJob) (err error) {
+	defer basic.RecoverPanic(&err)
+	return queue.handler(ctx, job)
+}
+
+func (queue *JobQueue) work(job QueueJob) {
+	defer queue.workers.Done()
+	defer queue.tokens.Release()
+
+	ctx, cancel := context.WithTimeout(queue.runCtx, queue.visibility)
+	err := queue.call(ctx, job)
+	cancel()
+
+	if err == nil {
+		queue.reportError(job, queue.store.Complete(job))
+		return
+	}
+
+	now := time.Now()
+	if job.FailedAt.IsZero() {
+		job.FailedAt = now
+	}
+	job.LastError = err.Error()
+
+	maxAttempts := job.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = queue.maxAttempts
+	}
+
+	if job.Attempts < maxAttempts && !errors.Is(err, ErrJobPermanent) {
+		if delay, ok := queue.backoff.NextDelay(job.Attempts, now.Sub(job.FailedAt)); ok {
+			job.RunAt = now.Add(delay)
+			if err := queue.store.Reschedule(job); err != nil {
+				queue.reportError(job, err)
+			} else {
+				queue.notify()
+			}
+			return
+		}
+	}
+
+	if err := queue.store.Bury(job); err != nil {
+		queue.reportError(job, err)
+	} else if queue.onDead != nil {
+		queue.onDead(job)
+	}
+}
+
+func (queue *JobQueue) reportError(job QueueJob, err error) {
+	if err != nil && queue.onError != nil {
+		queue.onError(job, err)
+	}
+}
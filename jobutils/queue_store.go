@@ -0,0 +1,351 @@
+package jobutils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/stl"
+)
+
+var (
+	ErrQueueJobExists    = errors.New("jobutils: queue job already exists")
+	ErrQueueJobNotFound  = errors.New("jobutils: queue job not found")
+	ErrQueueJobLeaseLost = errors.New("jobutils: queue job lease lost")
+)
+
+// JobStore 保存 JobQueue 的任务与死信，须并发安全。
+// 任务被领取时 Attempts 与 LeaseID 加一，并在可见性超时内对其他领取者不可见；
+// Complete、Reschedule、Bury 只在 LeaseID 与存储中一致（即租约仍有效）时生效，否则返回 ErrQueueJobLeaseLost
+type JobStore interface {
+	// Add 加入新任务，ID 已存在时返回 ErrQueueJobExists
+	Add(job QueueJob) error
+	// Lease 领取 RunAt 不晚于 now 的最早任务，将其 RunAt 推迟到 now+visibility
+	Lease(now time.Time, visibility time.Duration) (job QueueJob, ok bool, err error)
+	// Complete 删除执行成功的任务
+	Complete(job QueueJob) error
+	// Reschedule 按 job 的 RunAt、LastError 等更新任务，用于重试
+	Reschedule(job QueueJob) error
+	// Bury 将任务移入死信
+	Bury(job QueueJob) error
+	// NextRunAt 返回最早的 RunAt，没有任务时 ok 为 false
+	NextRunAt() (runAt time.Time, ok bool, err error)
+	// Len 返回任务个数，含正在执行的，不含死信
+	Len() (int, error)
+	// DeadLetters 返回全部死信，按进入先后排列
+	DeadLetters() ([]QueueJob, error)
+	// Revive 将死信重新放回队列，Attempts 清零（LeaseID 保留，旧租约仍然无效），于 runAt 执行
+	Revive(id string, runAt time.Time) error
+}
+
+// MemoryJobStore 为内存中的 JobStore，进程退出后任务丢失
+type MemoryJobStore struct {
+	mutex sync.Mutex
+	jobs  map[string]QueueJob
+	dead  []QueueJob
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs: map[string]QueueJob{},
+	}
+}
+
+func (store *MemoryJobStore) Add(job QueueJob) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if _, ok := store.jobs[job.ID]; ok {
+		return ErrQueueJobExists
+	}
+
+	store.jobs[job.ID] = job
+	return nil
+}
+
+func (store *MemoryJobStore) Lease(now time.Time, visibility time.Duration) (job QueueJob, ok bool, err error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	// 任务数通常不多，直接遍历；RunAt 相同时按入队先后
+	for _, candidate := range store.jobs {
+		if candidate.RunAt.After(now) {
+			continue
+		}
+
+		if !ok || candidate.RunAt.Before(job.RunAt) ||
+			candidate.RunAt.Equal(job.RunAt) && candidate.EnqueuedAt.Before(job.EnqueuedAt) {
+			job, ok = candidate, true
+		}
+	}
+
+	if !ok {
+		return
+	}
+
+	job.Attempts++
+	job.LeaseID++
+	job.RunAt = now.Add(visibility)
+	store.jobs[job.ID] = job
+
+	return job, true, nil
+}
+
+// leased 检查租约，调用方须持有锁
+func (store *MemoryJobStore) leased(job QueueJob) error {
+	stored, ok := store.jobs[job.ID]
+	if !ok {
+		return ErrQueueJobNotFound
+	}
+
+	if stored.LeaseID != job.LeaseID {
+		return ErrQueueJobLeaseLost
+	}
+
+	return nil
+}
+
+func (store *MemoryJobStore) Complete(job QueueJob) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if err := store.leased(job); err != nil {
+		return err
+	}
+
+	delete(store.jobs, job.ID)
+	return nil
+}
+
+func (store *MemoryJobStore) Reschedule(job QueueJob) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if err := store.leased(job); err != nil {
+		return err
+	}
+
+	store.jobs[job.ID] = job
+	return nil
+}
+
+func (store *MemoryJobStore) Bury(job QueueJob) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if err := store.leased(job); err != nil {
+		return err
+	}
+
+	delete(store.jobs, job.ID)
+	store.dead = append(store.dead, job)
+	return nil
+}
+
+func (store *MemoryJobStore) NextRunAt() (runAt time.Time, ok bool, err error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, job := range store.jobs {
+		if !ok || job.RunAt.Before(runAt) {
+			runAt, ok = job.RunAt, true
+		}
+	}
+
+	return
+}
+
+func (store *MemoryJobStore) Len() (int, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return len(store.jobs), nil
+}
+
+func (store *MemoryJobStore) DeadLetters() ([]QueueJob, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return append([]QueueJob(nil), store.dead...), nil
+}
+
+func (store *MemoryJobStore) Revive(id string, runAt time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for i, job := range store.dead {
+		if job.ID != id {
+			continue
+		}
+
+		if _, ok := store.jobs[id]; ok {
+			return ErrQueueJobExists
+		}
+
+		job.Attempts = 0
+		job.RunAt = runAt
+		job.FailedAt = time.Time{}
+		store.jobs[id] = job
+		store.dead = append(store.dead[:i], store.dead[i+1:]...)
+		return nil
+	}
+
+	return ErrQueueJobNotFound
+}
+
+type fileJobStoreData struct {
+	Jobs []QueueJob `json:"jobs"`
+	Dead []QueueJob `json:"dead"`
+}
+
+// FileJobStore 为以 JSON 文件持久化的 JobStore，进程重启后任务仍在；
+// 执行中的任务在可见性超时后重新执行。每次修改都重写整个文件（先写临时文件再改名），适合任务不多的场景
+type FileJobStore struct {
+	mutex  sync.Mutex
+	path   string
+	memory *MemoryJobStore
+}
+
+// NewFileJobStore 创建 FileJobStore，文件已存在时加载其中的任务
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	store := &FileJobStore{
+		path:   path,
+		memory: NewMemoryJobStore(),
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (store *FileJobStore) Path() string {
+	return store.path
+}
+
+func (store *FileJobStore) load() error {
+	var data fileJobStoreData
+
+	content, err := os.ReadFile(store.path)
+	if err == nil {
+		err = json.Unmarshal(content, &data)
+	} else if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	jobs := make(map[string]QueueJob, len(data.Jobs))
+	for _, job := range data.Jobs {
+		jobs[job.ID] = job
+	}
+
+	memory := store.memory
+	memory.mutex.Lock()
+	memory.jobs, memory.dead = jobs, data.Dead
+	memory.mutex.Unlock()
+
+	return nil
+}
+
+// update 执行修改并在有变化时保存，保存失败时从文件恢复，保持内存与文件一致
+func (store *FileJobStore) update(fn func() (changed bool, err error)) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if changed, err := fn(); err != nil || !changed {
+		return err
+	}
+
+	if err := store.save(); err != nil {
+		return stl.NewErrors(err, store.load()).Simplify()
+	}
+
+	return nil
+}
+
+func (store *FileJobStore) save() error {
+	memory := store.memory
+	memory.mutex.Lock()
+	data := fileJobStoreData{
+		Jobs: make([]QueueJob, 0, len(memory.jobs)),
+		Dead: memory.dead,
+	}
+	for _, job := range memory.jobs {
+		data.Jobs = append(data.Jobs, job)
+	}
+	memory.mutex.Unlock()
+
+	sort.Slice(data.Jobs, func(i, j int) bool { return data.Jobs[i].EnqueuedAt.Before(data.Jobs[j].EnqueuedAt) })
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tmp := store.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.path)
+}
+
+func (store *FileJobStore) Add(job QueueJob) error {
+	return store.update(func() (bool, error) {
+		return true, store.memory.Add(job)
+	})
+}
+
+func (store *FileJobStore) Lease(now time.Time, visibility time.Duration) (job QueueJob, ok bool, err error) {
+	// 领取须持久化，否则重启后 Attempts 丢失
+	err = store.update(func() (bool, error) {
+		job, ok, err = store.memory.Lease(now, visibility)
+		return ok, err
+	})
+	if err != nil {
+		return QueueJob{}, false, err
+	}
+	return
+}
+
+func (store *FileJobStore) Complete(job QueueJob) error {
+	return store.update(func() (bool, error) {
+		return true, store.memory.Complete(job)
+	})
+}
+
+func (store *FileJobStore) Reschedule(job QueueJob) error {
+	return store.update(func() (bool, error) {
+		return true, store.memory.Reschedule(job)
+	})
+}
+
+func (store *FileJobStore) Bury(job QueueJob) error {
+	return store.update(func() (bool, error) {
+		return true, store.memory.Bury(job)
+	})
+}
+
+func (store *FileJobStore) NextRunAt() (time.Time, bool, error) {
+	return store.memory.NextRunAt()
+}
+
+func (store *FileJobStore) Len() (int, error) {
+	return store.memory.Len()
+}
+
+func (store *FileJobStore) DeadLetters() ([]QueueJob, error) {
+	return store.memory.DeadLetters()
+}
+
+func (store *FileJobStore) Revive(id string, runAt time.Time) error {
+	return store.update(func() (bool, error) {
+		return true, store.memory.Revive(id, runAt)
+	})
+}
@@ -0,0 +1,324 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/stl"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	return assert.True(t, condition(), "condition not met in %s", timeout)
+}
+
+func storeLen(store JobStore) int {
+	n, _ := store.Len()
+	return n
+}
+
+func TestJobQueueWorkers(t *testing.T) {
+	var running, maxRunning, done atomic.Int32
+
+	store := NewMemoryJobStore()
+	queue := NewJobQueue(store, func(ctx context.Context, job QueueJob) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		done.Add(1)
+		return nil
+	}).WithWorkers(2)
+
+	for i := 0; i < 6; i++ {
+		_, err := queue.Enqueue(NewQueueJob("work", []byte(fmt.Sprint(i))))
+		assert.Nil(t, err)
+	}
+
+	_, err := queue.Enqueue(NewQueueJob("work", nil).WithID("fixed"))
+	assert.Nil(t, err)
+	_, err = queue.Enqueue(NewQueueJob("work", nil).WithID("fixed"))
+	assert.ErrorIs(t, err, ErrQueueJobExists)
+
+	assert.Nil(t, queue.Start(nil))
+	waitFor(t, time.Second, func() bool { return done.Load() == 7 })
+	assert.Nil(t, queue.Stop(nil))
+
+	assert.Equal(t, int32(2), maxRunning.Load())
+	assert.Equal(t, 0, storeLen(store))
+
+	_, err = queue.Enqueue(NewQueueJob("late", nil))
+	assert.ErrorIs(t, err, ErrJobQueueStopped)
+	assert.ErrorIs(t, queue.Start(nil), ErrJobQueueStopped)
+}
+
+func TestJobQueueDelay(t *testing.T) {
+	var mutex sync.Mutex
+	var order []string
+
+	queue := NewJobQueue(NewMemoryJobStore(), func(ctx context.Context, job QueueJob) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		order = append(order, job.Kind)
+		return nil
+	}).WithPollInterval(time.Hour)
+
+	assert.Nil(t, queue.Start(nil))
+	defer queue.Stop(nil)
+
+	start := time.Now()
+	_, err := queue.Enqueue(NewQueueJob("later", nil).WithDelay(40 * time.Millisecond))
+	assert.Nil(t, err)
+	_, err = queue.Enqueue(NewQueueJob("sooner", nil).WithDelay(20 * time.Millisecond))
+	assert.Nil(t, err)
+
+	waitFor(t, time.Second, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(order) == 2
+	})
+
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, []string{"sooner", "later"}, order)
+}
+
+func TestJobQueueRetryAndDeadLetter(t *testing.T) {
+	var attempts sync.Map
+	var dead atomic.Int32
+	var healed atomic.Bool
+
+	store := NewMemoryJobStore()
+	queue := NewJobQueue(store, func(ctx context.Context, job QueueJob) error {
+		n, _ := attempts.LoadOrStore(job.Kind, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+
+		switch job.Kind {
+		case "flaky":
+			if job.Attempts < 3 {
+				return errors.New("try again")
+			}
+		case "broken":
+			if !healed.Load() {
+				return errors.New("always fails")
+			}
+		case "invalid":
+			return fmt.Errorf("%w: bad payload", ErrJobPermanent)
+		case "panic":
+			panic("boom")
+		}
+		return nil
+	}).
+		WithMaxAttempts(3).
+		WithBackoff(stl.NewConstantBackoff(5 * time.Millisecond)).
+		WithDeadLetterCallback(func(job QueueJob) {
+			dead.Add(1)
+		})
+
+	for _, kind := range []string{"flaky", "broken", "invalid", "panic"} {
+		_, err := queue.Enqueue(NewQueueJob(kind, nil).WithID(kind))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, queue.Start(nil))
+	defer queue.Stop(nil)
+
+	waitFor(t, time.Second, func() bool { return dead.Load() == 3 && storeLen(store) == 0 })
+
+	count := func(kind string) int32 {
+		n, _ := attempts.Load(kind)
+		return n.(*atomic.Int32).Load()
+	}
+	assert.Equal(t, int32(3), count("flaky"))
+	assert.Equal(t, int32(3), count("broken"))
+	assert.Equal(t, int32(1), count("invalid"))
+	assert.Equal(t, int32(3), count("panic"))
+
+	letters, err := queue.DeadLetters()
+	assert.Nil(t, err)
+	assert.Len(t, letters, 3)
+	for _, letter := range letters {
+		assert.NotEmpty(t, letter.LastError)
+		assert.False(t, letter.FailedAt.IsZero())
+	}
+
+	healed.Store(true)
+	assert.Nil(t, queue.Revive("broken"))
+	assert.ErrorIs(t, queue.Revive("broken"), ErrQueueJobNotFound)
+
+	waitFor(t, time.Second, func() bool { return count("broken") == 4 && storeLen(store) == 0 })
+	letters, _ = queue.DeadLetters()
+	assert.Len(t, letters, 2)
+}
+
+func TestJobQueueVisibilityTimeout(t *testing.T) {
+	var succeeded atomic.Int32
+	var leaseLost atomic.Int32
+
+	store := NewMemoryJobStore()
+	queue := NewJobQueue(store, func(ctx context.Context, job QueueJob) error {
+		if job.Attempts == 1 {
+			// 超过可见性超时，被再次领取
+			time.Sleep(60 * time.Millisecond)
+			return nil
+		}
+		succeeded.Add(1)
+		return nil
+	}).
+		WithWorkers(2).
+		WithVisibilityTimeout(20 * time.Millisecond).
+		WithErrorCallback(func(job QueueJob, err error) {
+			if errors.Is(err, ErrQueueJobLeaseLost) || errors.Is(err, ErrQueueJobNotFound) {
+				leaseLost.Add(1)
+			}
+		})
+
+	_, err := queue.Enqueue(NewQueueJob("slow", nil))
+	assert.Nil(t, err)
+
+	assert.Nil(t, queue.Start(nil))
+	waitFor(t, time.Second, func() bool { return succeeded.Load() == 1 && leaseLost.Load() == 1 })
+	assert.Nil(t, queue.Stop(nil))
+	assert.Equal(t, 0, storeLen(store))
+}
+
+func TestJobQueueStopTimeout(t *testing.T) {
+	started := make(chan struct{})
+
+	store := NewMemoryJobStore()
+	queue := NewJobQueue(store, func(ctx context.Context, job QueueJob) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	_, err := queue.Enqueue(NewQueueJob("stuck", nil))
+	assert.Nil(t, err)
+	assert.Nil(t, queue.Start(nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, queue.Stop(ctx), context.DeadlineExceeded)
+
+	// 被取消的任务留在队列中，按退避时间重试而非等待可见性超时
+	waitFor(t, time.Second, func() bool {
+		runAt, ok, _ := store.NextRunAt()
+		return ok && runAt.Before(time.Now().Add(10*time.Second))
+	})
+
+	job, ok, err := store.Lease(time.Now().Add(time.Hour), time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, context.Canceled.Error(), job.LastError)
+}
+
+func TestFileJobStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	store, err := NewFileJobStore(path)
+	assert.Nil(t, err)
+	assert.Equal(t, path, store.Path())
+
+	queue := NewJobQueue(store, nil)
+	_, err = queue.Enqueue(NewQueueJob("email", []byte(`{"to":"a@example.com"}`)).WithID("mail-1").WithMaxAttempts(1))
+	assert.Nil(t, err)
+	_, err = queue.Enqueue(NewQueueJob("email", nil).WithID("mail-2").WithDelay(time.Hour))
+	assert.Nil(t, err)
+
+	// 模拟重启
+	store, err = NewFileJobStore(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, storeLen(store))
+
+	var payload []byte
+	queue = NewJobQueue(store, func(ctx context.Context, job QueueJob) error {
+		payload = job.Payload
+		return errors.New("smtp down")
+	})
+	assert.Nil(t, queue.Start(nil))
+	waitFor(t, time.Second, func() bool {
+		letters, _ := store.DeadLetters()
+		return len(letters) == 1
+	})
+	assert.Nil(t, queue.Stop(nil))
+	assert.Equal(t, `{"to":"a@example.com"}`, string(payload))
+
+	store, err = NewFileJobStore(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, storeLen(store))
+
+	letters, err := store.DeadLetters()
+	assert.Nil(t, err)
+	if assert.Len(t, letters, 1) {
+		assert.Equal(t, "mail-1", letters[0].ID)
+		assert.Equal(t, 1, letters[0].Attempts)
+		assert.Equal(t, "smtp down", letters[0].LastError)
+	}
+
+	runAt, ok, err := store.NextRunAt()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, runAt.After(time.Now().Add(50*time.Minute)))
+
+	_, err = NewFileJobStore(filepath.Join(t.TempDir(), "missing", "jobs.json"))
+	assert.Nil(t, err)
+}
+
+func TestMemoryJobStoreReviveLease(t *testing.T) {
+	store := NewMemoryJobStore()
+	assert.Nil(t, store.Add(QueueJob{ID: "job", RunAt: time.Now()}))
+
+	// 旧 worker 租约超时，任务被其他 worker 领取后移入死信
+	stale, ok, _ := store.Lease(time.Now(), time.Millisecond)
+	assert.True(t, ok)
+	next, ok, _ := store.Lease(time.Now().Add(time.Second), time.Minute)
+	assert.True(t, ok)
+	assert.Nil(t, store.Bury(next))
+
+	// 复活后 Attempts 清零，旧 worker 的 Attempts 与之相同，但租约已失效
+	assert.Nil(t, store.Revive("job", time.Now()))
+	revived, ok, _ := store.Lease(time.Now(), time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, stale.Attempts, revived.Attempts)
+
+	assert.ErrorIs(t, store.Complete(stale), ErrQueueJobLeaseLost)
+	assert.ErrorIs(t, store.Bury(stale), ErrQueueJobLeaseLost)
+	assert.Nil(t, store.Complete(revived))
+}
+
+func TestFileJobStoreSaveFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	store, err := NewFileJobStore(path)
+	assert.Nil(t, err)
+
+	// 路径被目录占用，保存与恢复都失败
+	assert.Nil(t, os.Mkdir(path, 0755))
+	err = store.Add(QueueJob{ID: "job"})
+
+	var errs stl.Errors
+	if assert.ErrorAs(t, err, &errs) {
+		assert.Len(t, errs, 2)
+	}
+}
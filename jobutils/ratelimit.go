@@ -0,0 +1,550 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRateLimiterStopped     = errors.New("jobutils: rate limiter stopped")
+	ErrRateLimitUnsatisfiable = errors.New("jobutils: rate limit can never be satisfied")
+)
+
+// RateLimiter 为限流器的公共接口
+type RateLimiter interface {
+	// Allow 当前允许时消耗一次配额并返回 true，否则不消耗
+	Allow() bool
+	// Wait 阻塞到允许为止，ctx 结束或限流器停止时返回错误且不消耗配额
+	Wait(ctx context.Context) error
+	// Reserve 立即预定一次配额，返回的预定说明还需等待多久
+	Reserve() *RateReservation
+	// Stop 停止限流器，此后 Allow 返回 false，Wait 返回 ErrRateLimiterStopped
+	Stop()
+}
+
+// RateInterval 将「每 interval 一次」换算为每秒次数
+func RateInterval(interval time.Duration) float64 {
+	if interval <= 0 {
+		return math.Inf(1)
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// RateReservation 为一次预定
+type RateReservation struct {
+	ok     bool
+	act    time.Time
+	cancel func(act time.Time)
+	once   sync.Once
+}
+
+// OK 返回预定是否成功，限流器已停止或请求永远无法满足时为 false
+func (reservation *RateReservation) OK() bool {
+	return reservation.ok
+}
+
+// Delay 返回距可以执行还需等待的时间
+func (reservation *RateReservation) Delay() time.Duration {
+	if !reservation.ok {
+		return 0
+	}
+	return max(time.Until(reservation.act), 0)
+}
+
+// Cancel 放弃尚未到期的预定，归还配额；已到期的预定视为已使用
+func (reservation *RateReservation) Cancel() {
+	if !reservation.ok || reservation.cancel == nil {
+		return
+	}
+
+	reservation.once.Do(func() {
+		reservation.cancel(reservation.act)
+	})
+}
+
+// rateLimiterCore 实现各限流器共用的停止与等待逻辑，具体算法由 reserve 提供：
+// reserve 在持有锁时调用，返回可以执行的时刻；wait 为 false 时只有立即可执行才消耗配额
+type rateLimiterCore struct {
+	mutex   sync.Mutex
+	stopped chan struct{}
+	once    sync.Once
+
+	reserve func(now time.Time, wait bool) (act time.Time, ok bool)
+	cancel  func(act time.Time, now time.Time)
+}
+
+func newRateLimiterCore() rateLimiterCore {
+	return rateLimiterCore{
+		stopped: make(chan struct{}),
+	}
+}
+
+func (core *rateLimiterCore) isStopped() bool {
+	select {
+	case <-core.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (core *rateLimiterCore) Allow() bool {
+	core.mutex.Lock()
+	defer core.mutex.Unlock()
+
+	if core.isStopped() {
+		return false
+	}
+
+	_, ok := core.reserve(time.Now(), false)
+	return ok
+}
+
+func (core *rateLimiterCore) Reserve() *RateReservation {
+	core.mutex.Lock()
+	defer core.mutex.Unlock()
+
+	if core.isStopped() {
+		return &RateReservation{}
+	}
+
+	act, ok := core.reserve(time.Now(), true)
+	return &RateReservation{
+		ok:  ok,
+		act: act,
+		cancel: func(act time.Time) {
+			core.mutex.Lock()
+			defer core.mutex.Unlock()
+
+			if now := time.Now(); act.After(now) {
+				core.cancel(act, now)
+			}
+		},
+	}
+}
+
+func (core *rateLimiterCore) Wait(ctx context.Context) error {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reservation := core.Reserve()
+	if !reservation.OK() {
+		if core.isStopped() {
+			return ErrRateLimiterStopped
+		}
+		return ErrRateLimitUnsatisfiable
+	}
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	case <-core.stopped:
+		reservation.Cancel()
+		return ErrRateLimiterStopped
+	}
+}
+
+func (core *rateLimiterCore) Stop() {
+	core.once.Do(func() {
+		close(core.stopped)
+	})
+}
+
+// TokenBucket 为令牌桶限流器：以 rate 次每秒的速度补充令牌，最多积攒 burst 个，允许短时突发。
+// 不启动协程，令牌在调用时按流逝的时间补充。
+type TokenBucket struct {
+	rateLimiterCore
+
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket 创建令牌桶，初始时桶是满的
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	bucket := &TokenBucket{
+		rateLimiterCore: newRateLimiterCore(),
+		rate:            rate,
+		burst:           burst,
+		tokens:          float64(burst),
+		last:            time.Now(),
+	}
+	bucket.rateLimiterCore.reserve = bucket.reserve
+	bucket.rateLimiterCore.cancel = bucket.cancel
+	return bucket
+}
+
+func (bucket *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = min(float64(bucket.burst), bucket.tokens+elapsed.Seconds()*bucket.rate)
+		bucket.last = now
+	}
+}
+
+func (bucket *TokenBucket) reserve(now time.Time, wait bool) (time.Time, bool) {
+	if bucket.burst <= 0 {
+		return now, false
+	}
+
+	bucket.refill(now)
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return now, true
+	}
+
+	// rate 为 0 时用完初始的令牌后不再补充
+	if !wait || bucket.rate <= 0 {
+		return now, false
+	}
+
+	// 预支令牌，令牌数为负表示已被预定的量
+	lack := 1 - bucket.tokens
+	bucket.tokens--
+	return now.Add(time.Duration(lack / bucket.rate * float64(time.Second))), true
+}
+
+func (bucket *TokenBucket) cancel(act time.Time, now time.Time) {
+	bucket.refill(now)
+	bucket.tokens = min(float64(bucket.burst), bucket.tokens+1)
+}
+
+// Tokens 返回当前可用的令牌数，有预定未到期时为负
+func (bucket *TokenBucket) Tokens() float64 {
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	bucket.refill(time.Now())
+	return bucket.tokens
+}
+
+// SlidingWindowLimiter 为滑动窗口计数限流器：任意长度为 window 的时间段内至多 limit 次。
+// 按固定窗口计数，用上一个窗口的计数按重叠比例加权估计滑动窗口内的次数，内存占用固定。
+type SlidingWindowLimiter struct {
+	rateLimiterCore
+
+	limit  int
+	window time.Duration
+	counts map[int64]int // 窗口序号 -> 次数，包括预定到未来窗口的次数
+}
+
+func NewSlidingWindowLimiter(limit int, window time.Duration) *SlidingWindowLimiter {
+	limiter := &SlidingWindowLimiter{
+		rateLimiterCore: newRateLimiterCore(),
+		limit:           limit,
+		window:          window,
+		counts:          map[int64]int{},
+	}
+	limiter.rateLimiterCore.reserve = limiter.reserve
+	limiter.rateLimiterCore.cancel = limiter.cancel
+	return limiter
+}
+
+func (limiter *SlidingWindowLimiter) index(t time.Time) int64 {
+	return t.UnixNano() / int64(limiter.window)
+}
+
+func (limiter *SlidingWindowLimiter) start(index int64) time.Time {
+	return time.Unix(0, index*int64(limiter.window))
+}
+
+// earliest 返回 now 之后估计次数加一不超过 limit 的最早时刻
+func (limiter *SlidingWindowLimiter) earliest(now time.Time) time.Time {
+	for index := limiter.index(now); ; index++ {
+		current, previous := limiter.counts[index], limiter.counts[index-1]
+		if current+1 > limiter.limit {
+			continue
+		}
+
+		at := limiter.start(index)
+		if previous > 0 {
+			// previous*(1-fraction) + current + 1 <= limit
+			fraction := 1 - float64(limiter.limit-current-1)/float64(previous)
+			if fraction > 0 {
+				at = at.Add(time.Duration(math.Ceil(fraction * float64(limiter.window))))
+			}
+		}
+
+		if at.Before(now) {
+			at = now
+		}
+
+		if at.Before(limiter.start(index + 1)) {
+			return at
+		}
+	}
+}
+
+func (limiter *SlidingWindowLimiter) reserve(now time.Time, wait bool) (time.Time, bool) {
+	if limiter.limit <= 0 || limiter.window <= 0 {
+		return now, false
+	}
+
+	// 清理不再参与估计的旧窗口
+	current := limiter.index(now)
+	for index := range limiter.counts {
+		if index < current-1 {
+			delete(limiter.counts, index)
+		}
+	}
+
+	at := limiter.earliest(now)
+	if at.After(now) && !wait {
+		return now, false
+	}
+
+	limiter.counts[limiter.index(at)]++
+	return at, true
+}
+
+func (limiter *SlidingWindowLimiter) cancel(act time.Time, now time.Time) {
+	index := limiter.index(act)
+	if limiter.counts[index] > 0 {
+		limiter.counts[index]--
+	}
+}
+
+// GCRALimiter 为通用信元速率算法（GCRA）限流器：只记录一个理论到达时间，
+// 效果等价于令牌桶，但请求均匀间隔 1/rate，最多允许 burst 个突发。
+type GCRALimiter struct {
+	rateLimiterCore
+
+	interval  time.Duration
+	tolerance time.Duration
+	tat       time.Time // theoretical arrival time
+}
+
+func NewGCRALimiter(rate float64, burst int) *GCRALimiter {
+	limiter := &GCRALimiter{
+		rateLimiterCore: newRateLimiterCore(),
+	}
+
+	if rate > 0 && burst > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / rate)
+		limiter.tolerance = limiter.interval * time.Duration(burst)
+	}
+
+	limiter.rateLimiterCore.reserve = limiter.reserve
+	limiter.rateLimiterCore.cancel = limiter.cancel
+	return limiter
+}
+
+func (limiter *GCRALimiter) reserve(now time.Time, wait bool) (time.Time, bool) {
+	if limiter.tolerance <= 0 {
+		return now, false
+	}
+
+	tat := limiter.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	next := tat.Add(limiter.interval)
+	at := next.Add(-limiter.tolerance)
+	if at.Before(now) {
+		at = now
+	} else if at.After(now) && !wait {
+		return now, false
+	}
+
+	limiter.tat = next
+	return at, true
+}
+
+func (limiter *GCRALimiter) cancel(act time.Time, now time.Time) {
+	limiter.tat = limiter.tat.Add(-limiter.interval)
+}
+
+type keyedRateLimiterEntry struct {
+	limiter  RateLimiter
+	lastUsed time.Time
+	waiters  int // 进行中的 Wait 个数，大于 0 时不淘汰
+}
+
+// KeyedRateLimiter 按键分别限流（如每个用户、每个主机），限流器在首次使用时由 factory 创建，
+// 超过 idleTimeout 未使用的键会被淘汰。被淘汰的键再次使用时重新创建，限流状态从头开始，
+// 因此 idleTimeout 应不短于限流器完全恢复所需的时间。有 Wait 进行中的键不会被淘汰。
+type KeyedRateLimiter[Key comparable] struct {
+	mutex       sync.Mutex
+	limiters    map[Key]*keyedRateLimiterEntry
+	factory     func(Key) RateLimiter
+	idleTimeout time.Duration
+	stopped     chan struct{}
+	once        sync.Once
+}
+
+// NewKeyedRateLimiter 创建按键限流器，idleTimeout > 0 时启动后台协程定期淘汰，Stop 后退出
+func NewKeyedRateLimiter[Key comparable](factory func(Key) RateLimiter, idleTimeout time.Duration) *KeyedRateLimiter[Key] {
+	keyed := &KeyedRateLimiter[Key]{
+		limiters:    map[Key]*keyedRateLimiterEntry{},
+		factory:     factory,
+		idleTimeout: idleTimeout,
+		stopped:     make(chan struct{}),
+	}
+
+	if idleTimeout > 0 {
+		go keyed.evictLoop()
+	}
+
+	return keyed
+}
+
+func (keyed *KeyedRateLimiter[Key]) evictLoop() {
+	ticker := time.NewTicker(max(keyed.idleTimeout/2, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-keyed.stopped:
+			return
+		case <-ticker.C:
+			keyed.EvictIdle()
+		}
+	}
+}
+
+// Get 返回键对应的限流器，不存在时创建；限流器停止后返回的限流器均已停止。
+// 直接调用返回的限流器不受淘汰保护，键被淘汰后它会被停止，等待应使用 KeyedRateLimiter.Wait
+func (keyed *KeyedRateLimiter[Key]) Get(key Key) RateLimiter {
+	keyed.mutex.Lock()
+	defer keyed.mutex.Unlock()
+
+	return keyed.get(key).limiter
+}
+
+// get 需持有 mutex 调用
+func (keyed *KeyedRateLimiter[Key]) get(key Key) *keyedRateLimiterEntry {
+	entry, ok := keyed.limiters[key]
+	if !ok {
+		entry = &keyedRateLimiterEntry{limiter: keyed.factory(key)}
+		select {
+		case <-keyed.stopped:
+			entry.limiter.Stop()
+		default:
+			keyed.limiters[key] = entry
+		}
+	}
+
+	entry.lastUsed = time.Now()
+	return entry
+}
+
+func (keyed *KeyedRateLimiter[Key]) Allow(key Key) bool {
+	return keyed.Get(key).Allow()
+}
+
+// Wait 等待期间该键不会被淘汰
+func (keyed *KeyedRateLimiter[Key]) Wait(ctx context.Context, key Key) error {
+	keyed.mutex.Lock()
+	entry := keyed.get(key)
+	entry.waiters++
+	keyed.mutex.Unlock()
+
+	defer func() {
+		keyed.mutex.Lock()
+		entry.waiters--
+		entry.lastUsed = time.Now()
+		keyed.mutex.Unlock()
+	}()
+
+	return entry.limiter.Wait(ctx)
+}
+
+// Reserve 预约的时间点之前该键不会因空闲被淘汰
+func (keyed *KeyedRateLimiter[Key]) Reserve(key Key) *RateReservation {
+	keyed.mutex.Lock()
+	defer keyed.mutex.Unlock()
+
+	entry := keyed.get(key)
+	reservation := entry.limiter.Reserve()
+	if reservation.OK() {
+		entry.lastUsed = entry.lastUsed.Add(reservation.Delay())
+	}
+
+	return reservation
+}
+
+// Len 返回当前保留的键个数
+func (keyed *KeyedRateLimiter[Key]) Len() int {
+	keyed.mutex.Lock()
+	defer keyed.mutex.Unlock()
+	return len(keyed.limiters)
+}
+
+// Evict 淘汰指定的键并停止其限流器，返回是否淘汰；有 Wait 进行中时不淘汰
+func (keyed *KeyedRateLimiter[Key]) Evict(key Key) bool {
+	keyed.mutex.Lock()
+	entry, ok := keyed.limiters[key]
+	ok = ok && entry.waiters == 0
+	if ok {
+		delete(keyed.limiters, key)
+	}
+	keyed.mutex.Unlock()
+
+	if ok {
+		entry.limiter.Stop()
+	}
+
+	return ok
+}
+
+// EvictIdle 淘汰超过 idleTimeout 未使用的键，返回淘汰的个数
+func (keyed *KeyedRateLimiter[Key]) EvictIdle() int {
+	if keyed.idleTimeout <= 0 {
+		return 0
+	}
+
+	deadline := time.Now().Add(-keyed.idleTimeout)
+
+	keyed.mutex.Lock()
+	var evicted []RateLimiter
+	for key, entry := range keyed.limiters {
+		if entry.waiters == 0 && entry.lastUsed.Before(deadline) {
+			evicted = append(evicted, entry.limiter)
+			delete(keyed.limiters, key)
+		}
+	}
+	keyed.mutex.Unlock()
+
+	for _, limiter := range evicted {
+		limiter.Stop()
+	}
+
+	return len(evicted)
+}
+
+// Stop 停止后台淘汰与全部限流器
+func (keyed *KeyedRateLimiter[Key]) Stop() {
+	keyed.once.Do(func() {
+		close(keyed.stopped)
+	})
+
+	keyed.mutex.Lock()
+	limiters := keyed.limiters
+	keyed.limiters = map[Key]*keyedRateLimiterEntry{}
+	keyed.mutex.Unlock()
+
+	for _, entry := range limiters {
+		entry.limiter.Stop()
+	}
+}
@@ -0,0 +1,165 @@
+package jobutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRateLimiterBurst(t *testing.T, limiter RateLimiter, burst int) {
+	for i := 0; i < burst; i++ {
+		assert.True(t, limiter.Allow(), "allow #%d", i)
+	}
+	assert.False(t, limiter.Allow())
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(100, 5)
+	testRateLimiterBurst(t, bucket, 5)
+
+	// 10ms 补充一个令牌
+	reservation := bucket.Reserve()
+	assert.True(t, reservation.OK())
+	assert.InDelta(t, 10*time.Millisecond, reservation.Delay(), float64(2*time.Millisecond))
+	assert.Less(t, bucket.Tokens(), 0.0)
+
+	reservation.Cancel()
+	reservation.Cancel()
+	assert.GreaterOrEqual(t, bucket.Tokens(), 0.0)
+
+	start := time.Now()
+	assert.Nil(t, bucket.Wait(context.Background()))
+	assert.Nil(t, bucket.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	assert.False(t, NewTokenBucket(100, 0).Reserve().OK())
+	bucket = NewTokenBucket(0, 1)
+	assert.True(t, bucket.Allow())
+	assert.ErrorIs(t, bucket.Wait(nil), ErrRateLimitUnsatisfiable)
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	bucket := NewTokenBucket(1, 1)
+	assert.True(t, bucket.Allow())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bucket.Wait(ctx), context.DeadlineExceeded)
+
+	// 取消的等待归还了预定的令牌
+	assert.InDelta(t, 0, bucket.Tokens(), 0.05)
+}
+
+func TestRateLimiterStop(t *testing.T) {
+	for _, limiter := range []RateLimiter{
+		NewTokenBucket(1, 1),
+		NewSlidingWindowLimiter(1, time.Second),
+		NewGCRALimiter(1, 1),
+	} {
+		assert.True(t, limiter.Allow())
+
+		done := make(chan error)
+		go func() {
+			done <- limiter.Wait(context.Background())
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		limiter.Stop()
+		limiter.Stop()
+
+		assert.ErrorIs(t, <-done, ErrRateLimiterStopped)
+		assert.False(t, limiter.Allow())
+		assert.False(t, limiter.Reserve().OK())
+		assert.ErrorIs(t, limiter.Wait(context.Background()), ErrRateLimiterStopped)
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	limiter := NewSlidingWindowLimiter(10, 50*time.Millisecond)
+	testRateLimiterBurst(t, limiter, 10)
+
+	reservation := limiter.Reserve()
+	assert.True(t, reservation.OK())
+	assert.Greater(t, reservation.Delay(), time.Duration(0))
+	assert.LessOrEqual(t, reservation.Delay(), 100*time.Millisecond)
+
+	// 任意 window 内不超过 limit：连续 Wait 20 次至少跨越一个窗口
+	limiter = NewSlidingWindowLimiter(10, 50*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, limiter.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond)
+
+	assert.False(t, NewSlidingWindowLimiter(0, time.Second).Allow())
+}
+
+func TestGCRALimiter(t *testing.T) {
+	limiter := NewGCRALimiter(100, 3)
+	testRateLimiterBurst(t, limiter, 3)
+
+	reservation := limiter.Reserve()
+	assert.True(t, reservation.OK())
+	assert.InDelta(t, 10*time.Millisecond, reservation.Delay(), float64(2*time.Millisecond))
+
+	reservation.Cancel()
+	assert.False(t, limiter.Allow())
+
+	time.Sleep(12 * time.Millisecond)
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+
+	assert.Equal(t, 50.0, RateInterval(20*time.Millisecond))
+}
+
+func TestKeyedRateLimiter(t *testing.T) {
+	keyed := NewKeyedRateLimiter(func(key string) RateLimiter {
+		return NewTokenBucket(1, 2)
+	}, 20*time.Millisecond)
+	defer keyed.Stop()
+
+	testRateLimiterBurst(t, keyed.Get("alice"), 2)
+	assert.True(t, keyed.Allow("bob"))
+	assert.True(t, keyed.Reserve("bob").OK())
+	assert.False(t, keyed.Allow("bob"))
+	assert.Equal(t, 2, keyed.Len())
+
+	assert.True(t, keyed.Evict("bob"))
+	assert.False(t, keyed.Evict("bob"))
+	assert.Equal(t, 1, keyed.Len())
+	assert.True(t, keyed.Allow("bob"))
+
+	assert.Eventually(t, func() bool { return keyed.Len() == 0 }, time.Second, 5*time.Millisecond)
+
+	keyed.Stop()
+	assert.Equal(t, 0, keyed.Len())
+	assert.False(t, keyed.Allow("carol"))
+	assert.ErrorIs(t, keyed.Wait(context.Background(), "carol"), ErrRateLimiterStopped)
+}
+
+func TestKeyedRateLimiterEvictWaiting(t *testing.T) {
+	keyed := NewKeyedRateLimiter(func(key string) RateLimiter {
+		return NewTokenBucket(10, 1)
+	}, 20*time.Millisecond)
+	defer keyed.Stop()
+
+	assert.True(t, keyed.Allow("alice"))
+
+	done := make(chan error)
+	go func() {
+		done <- keyed.Wait(context.Background(), "alice")
+	}()
+
+	// 等待中的键超过 idleTimeout 也不会被淘汰，限流器不会被停止
+	time.Sleep(40 * time.Millisecond)
+	assert.False(t, keyed.Evict("alice"))
+	assert.Equal(t, 0, keyed.EvictIdle())
+	assert.Equal(t, 1, keyed.Len())
+	assert.Nil(t, <-done)
+
+	// 刚用完的令牌仍然生效
+	assert.False(t, keyed.Allow("alice"))
+	assert.Eventually(t, func() bool { return keyed.Len() == 0 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,463 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+)
+
+var (
+	ErrSchedulerStopped     = errors.New("jobutils: scheduler is stopped")
+	ErrScheduledJobExists   = errors.New("jobutils: scheduled job already exists")
+	ErrScheduledJobNotFound = errors.New("jobutils: scheduled job not found")
+)
+
+// OverlapPolicy 决定上一次执行尚未结束时如何处理本次执行
+type OverlapPolicy int
+
+const (
+	// OverlapSkip 跳过本次，在历史中记为 Skipped
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue 排队，上一次结束后立即执行；排队数达到上限（默认 1）时跳过本次
+	OverlapQueue
+	// OverlapReplace 取消上一次（其 ctx 被取消），立即执行本次
+	OverlapReplace
+)
+
+// JobRun 为一次执行的记录
+type JobRun struct {
+	Scheduled time.Time // 计划时间，不含抖动
+	Started   time.Time
+	Finished  time.Time
+	Skipped   bool
+	Err       error
+}
+
+func (run JobRun) Duration() time.Duration {
+	return run.Finished.Sub(run.Started)
+}
+
+// ScheduledJob 为按日程执行的任务，须在加入 Scheduler 前完成配置
+type ScheduledJob struct {
+	name        string
+	schedule    Schedule
+	fn          func(ctx context.Context) error
+	overlap     OverlapPolicy
+	jitter      time.Duration
+	historySize int
+	maxPending  int
+
+	mutex   sync.Mutex
+	stop    chan struct{}
+	next    time.Time
+	runID   uint64
+	cancels map[uint64]context.CancelFunc
+	pending []time.Time
+	removed bool
+	history []JobRun
+}
+
+// NewScheduledJob 创建任务，默认跳过重叠的执行，保留最近 10 次执行记录
+func NewScheduledJob(name string, schedule Schedule, fn func(ctx context.Context) error) *ScheduledJob {
+	return &ScheduledJob{
+		name:        name,
+		schedule:    schedule,
+		fn:          fn,
+		historySize: 10,
+		maxPending:  1,
+		cancels:     map[uint64]context.CancelFunc{},
+	}
+}
+
+func (job *ScheduledJob) WithOverlap(overlap OverlapPolicy) *ScheduledJob {
+	job.overlap = overlap
+	return job
+}
+
+// WithJitter 每次执行随机推迟 [0, jitter)，避免多个实例同时执行
+func (job *ScheduledJob) WithJitter(jitter time.Duration) *ScheduledJob {
+	job.jitter = jitter
+	return job
+}
+
+// WithMaxPending 设置 OverlapQueue 下最多排队的执行个数，超出的执行记为 Skipped；
+// 默认为 1，即持续超时的任务恢复后只补执行一次
+func (job *ScheduledJob) WithMaxPending(n int) *ScheduledJob {
+	job.maxPending = max(n, 1)
+	return job
+}
+
+func (job *ScheduledJob) WithHistorySize(size int) *ScheduledJob {
+	job.historySize = max(size, 0)
+	return job
+}
+
+func (job *ScheduledJob) Name() string {
+	return job.name
+}
+
+func (job *ScheduledJob) Schedule() Schedule {
+	return job.schedule
+}
+
+// Next 返回下一次计划执行时间（不含抖动），尚未启动时按当前时间计算
+func (job *ScheduledJob) Next() time.Time {
+	job.mutex.Lock()
+	next := job.next
+	job.mutex.Unlock()
+
+	if next.IsZero() {
+		next = job.schedule.Next(time.Now())
+	}
+	return next
+}
+
+// Preview 预览之后 n 次计划执行时间
+func (job *ScheduledJob) Preview(n int) []time.Time {
+	next := job.Next()
+	if next.IsZero() || n <= 0 {
+		return nil
+	}
+	return append([]time.Time{next}, NextTimes(job.schedule, next, n-1)...)
+}
+
+// Running 返回正在执行的个数
+func (job *ScheduledJob) Running() int {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	return len(job.cancels)
+}
+
+// History 返回最近的执行记录，按完成先后排列
+func (job *ScheduledJob) History() []JobRun {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	return append([]JobRun(nil), job.history...)
+}
+
+func (job *ScheduledJob) LastRun() (run JobRun, ok bool) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if len(job.history) == 0 {
+		return
+	}
+	return job.history[len(job.history)-1], true
+}
+
+// record 调用方须持有锁
+func (job *ScheduledJob) record(run JobRun) {
+	if job.historySize == 0 {
+		return
+	}
+
+	job.history = append(job.history, run)
+	if n := len(job.history) - job.historySize; n > 0 {
+		job.history = append(job.history[:0], job.history[n:]...)
+	}
+}
+
+func (job *ScheduledJob) jitterDelay() time.Duration {
+	if job.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(job.jitter)))
+}
+
+func (job *ScheduledJob) call(ctx context.Context) (err error) {
+	defer basic.RecoverPanic(&err)
+	return job.fn(ctx)
+}
+
+// Scheduler 按日程执行任务，每个任务一个后台协程等待下一次执行时间
+type Scheduler struct {
+	mutex   sync.Mutex
+	jobs    map[string]*ScheduledJob
+	parent  context.Context
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	stopped atomic.Bool
+	loops   sync.WaitGroup
+	runs    sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		jobs:   map[string]*ScheduledJob{},
+		parent: bgCtx,
+	}
+}
+
+// WithContext 设置任务执行的父 ctx，须在 Start 前调用
+func (scheduler *Scheduler) WithContext(ctx context.Context) *Scheduler {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+	scheduler.parent = ctx
+	return scheduler
+}
+
+// Add 加入任务，已启动时立即开始调度
+func (scheduler *Scheduler) Add(job *ScheduledJob) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.stopped.Load() {
+		return ErrSchedulerStopped
+	}
+
+	if _, ok := scheduler.jobs[job.name]; ok {
+		return ErrScheduledJobExists
+	}
+
+	job.mutex.Lock()
+	job.removed = false
+	job.mutex.Unlock()
+
+	scheduler.jobs[job.name] = job
+	if scheduler.started {
+		scheduler.startLoop(job)
+	}
+
+	return nil
+}
+
+// AddFunc 按 ParseSchedule 解析 spec 并加入任务，任务使用默认配置
+func (scheduler *Scheduler) AddFunc(name, spec string, fn func(ctx context.Context) error) (*ScheduledJob, error) {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	job := NewScheduledJob(name, schedule, fn)
+	if err := scheduler.Add(job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Remove 移除任务，不再调度并丢弃排队的执行，正在执行的不受影响
+func (scheduler *Scheduler) Remove(name string) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	job, ok := scheduler.jobs[name]
+	if !ok {
+		return ErrScheduledJobNotFound
+	}
+
+	delete(scheduler.jobs, name)
+	if job.stop != nil {
+		close(job.stop)
+		job.stop = nil
+	}
+
+	job.mutex.Lock()
+	job.removed = true
+	job.pending = nil
+	job.mutex.Unlock()
+
+	return nil
+}
+
+func (scheduler *Scheduler) Job(name string) (*ScheduledJob, bool) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	job, ok := scheduler.jobs[name]
+	return job, ok
+}
+
+// Jobs 返回全部任务，按名称排序
+func (scheduler *Scheduler) Jobs() []*ScheduledJob {
+	scheduler.mutex.Lock()
+	jobs := make([]*ScheduledJob, 0, len(scheduler.jobs))
+	for _, job := range scheduler.jobs {
+		jobs = append(jobs, job)
+	}
+	scheduler.mutex.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].name < jobs[j].name })
+	return jobs
+}
+
+func (scheduler *Scheduler) Start() error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.stopped.Load() {
+		return ErrSchedulerStopped
+	}
+
+	if scheduler.started {
+		return nil
+	}
+
+	scheduler.started = true
+	scheduler.ctx, scheduler.cancel = context.WithCancel(scheduler.parent)
+	for _, job := range scheduler.jobs {
+		scheduler.startLoop(job)
+	}
+
+	return nil
+}
+
+// Stop 停止调度并丢弃排队的执行，等待正在执行的任务结束；
+// ctx 先结束时取消正在执行的任务并返回 ctx.Err()
+func (scheduler *Scheduler) Stop(ctx context.Context) error {
+	if ctx == nil {
+		ctx = bgCtx
+	}
+
+	scheduler.mutex.Lock()
+	if scheduler.stopped.Swap(true) {
+		scheduler.mutex.Unlock()
+		return nil
+	}
+
+	for _, job := range scheduler.jobs {
+		if job.stop != nil {
+			close(job.stop)
+			job.stop = nil
+		}
+	}
+	scheduler.mutex.Unlock()
+
+	// 调度协程退出后不会再有新的执行
+	scheduler.loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.runs.Wait()
+		close(done)
+	}()
+
+	defer func() {
+		if scheduler.cancel != nil {
+			scheduler.cancel()
+		}
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startLoop 调用方须持有锁
+func (scheduler *Scheduler) startLoop(job *ScheduledJob) {
+	stop := make(chan struct{})
+	job.stop = stop
+
+	scheduler.loops.Add(1)
+	go scheduler.loop(job, stop)
+}
+
+func (scheduler *Scheduler) loop(job *ScheduledJob, stop <-chan struct{}) {
+	defer scheduler.loops.Done()
+
+	last := time.Now()
+	for {
+		// 错过的执行（如进程挂起）直接跳过，不补执行
+		now := time.Now()
+		scheduled := job.schedule.Next(last)
+		for !scheduled.IsZero() && scheduled.Before(now) {
+			scheduled = job.schedule.Next(scheduled)
+		}
+
+		job.mutex.Lock()
+		job.next = scheduled
+		job.mutex.Unlock()
+
+		if scheduled.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(scheduled.Add(job.jitterDelay())))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		scheduler.fire(job, scheduled)
+		last = scheduled
+	}
+}
+
+func (scheduler *Scheduler) fire(job *ScheduledJob, scheduled time.Time) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	// 调度协程可能在 Remove 或 Stop 之后才到达这里
+	if job.removed || scheduler.stopped.Load() {
+		return
+	}
+
+	if len(job.cancels) > 0 {
+		switch job.overlap {
+		case OverlapSkip:
+			job.record(JobRun{Scheduled: scheduled, Skipped: true})
+			return
+		case OverlapQueue:
+			if len(job.pending) >= job.maxPending {
+				job.record(JobRun{Scheduled: scheduled, Skipped: true})
+			} else {
+				job.pending = append(job.pending, scheduled)
+			}
+			return
+		case OverlapReplace:
+			for _, cancel := range job.cancels {
+				cancel()
+			}
+		}
+	}
+
+	job.runID++
+	id := job.runID
+	ctx, cancel := context.WithCancel(scheduler.ctx)
+	job.cancels[id] = cancel
+
+	scheduler.runs.Add(1)
+	go scheduler.run(job, id, ctx, scheduled)
+}
+
+// run 执行任务，结束后在同一协程内继续执行排队的
+func (scheduler *Scheduler) run(job *ScheduledJob, id uint64, ctx context.Context, scheduled time.Time) {
+	defer scheduler.runs.Done()
+
+	for {
+		run := JobRun{Scheduled: scheduled, Started: time.Now()}
+		run.Err = job.call(ctx)
+		run.Finished = time.Now()
+
+		job.mutex.Lock()
+		job.record(run)
+		job.cancels[id]()
+
+		if len(job.pending) == 0 || job.removed || scheduler.stopped.Load() {
+			delete(job.cancels, id)
+			job.pending = nil
+			job.mutex.Unlock()
+			return
+		}
+
+		scheduled = job.pending[0]
+		job.pending = job.pending[1:]
+		ctx, job.cancels[id] = context.WithCancel(scheduler.ctx)
+		job.mutex.Unlock()
+	}
+}
@@ -0,0 +1,230 @@
+package jobutils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerEvery(t *testing.T) {
+	var calls atomic.Int32
+
+	scheduler := NewScheduler()
+	job, err := scheduler.AddFunc("tick", "@every 20ms", func(ctx context.Context) error {
+		if calls.Add(1) == 2 {
+			return errors.New("second failed")
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+
+	_, err = scheduler.AddFunc("tick", "@every 1s", nil)
+	assert.ErrorIs(t, err, ErrScheduledJobExists)
+	_, err = scheduler.AddFunc("bad", "* * *", nil)
+	assert.ErrorIs(t, err, ErrCronSyntax)
+
+	assert.Nil(t, scheduler.Start())
+	time.Sleep(110 * time.Millisecond)
+	assert.Nil(t, scheduler.Stop(nil))
+
+	n := int(calls.Load())
+	assert.GreaterOrEqual(t, n, 4)
+
+	history := job.History()
+	assert.Len(t, history, n)
+	assert.NotNil(t, history[1].Err)
+	assert.False(t, history[0].Started.Before(history[0].Scheduled))
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, n, int(calls.Load()))
+
+	assert.ErrorIs(t, scheduler.Start(), ErrSchedulerStopped)
+	assert.ErrorIs(t, scheduler.Add(NewScheduledJob("late", NewEverySchedule(time.Second), nil)), ErrSchedulerStopped)
+}
+
+func TestSchedulerOverlapSkip(t *testing.T) {
+	var calls atomic.Int32
+
+	job := NewScheduledJob("slow", NewEverySchedule(10*time.Millisecond), func(ctx context.Context) error {
+		calls.Add(1)
+		time.Sleep(45 * time.Millisecond)
+		return nil
+	})
+
+	scheduler := NewScheduler()
+	assert.Nil(t, scheduler.Add(job))
+	assert.Nil(t, scheduler.Start())
+	time.Sleep(70 * time.Millisecond)
+	assert.Nil(t, scheduler.Stop(nil))
+
+	assert.LessOrEqual(t, calls.Load(), int32(2))
+
+	skipped := 0
+	for _, run := range job.History() {
+		if run.Skipped {
+			skipped++
+		}
+	}
+	assert.GreaterOrEqual(t, skipped, 2)
+}
+
+func TestSchedulerOverlapQueue(t *testing.T) {
+	var calls, concurrent, maxConcurrent atomic.Int32
+
+	job := NewScheduledJob("queued", NewEverySchedule(10*time.Millisecond), func(ctx context.Context) error {
+		n := concurrent.Add(1)
+		defer concurrent.Add(-1)
+		if n > maxConcurrent.Load() {
+			maxConcurrent.Store(n)
+		}
+
+		if calls.Add(1) == 1 {
+			time.Sleep(35 * time.Millisecond)
+		}
+		return nil
+	}).WithOverlap(OverlapQueue).WithMaxPending(5)
+
+	scheduler := NewScheduler()
+	assert.Nil(t, scheduler.Add(job))
+	assert.Nil(t, scheduler.Start())
+	time.Sleep(65 * time.Millisecond)
+	assert.Nil(t, scheduler.Stop(nil))
+
+	assert.Equal(t, int32(1), maxConcurrent.Load())
+	assert.GreaterOrEqual(t, calls.Load(), int32(5))
+
+	for _, run := range job.History() {
+		assert.False(t, run.Skipped)
+	}
+}
+
+func TestSchedulerOverlapQueueCoalesce(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	job := NewScheduledJob("coalesced", NewEverySchedule(5*time.Millisecond), func(ctx context.Context) error {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+		return nil
+	}).WithOverlap(OverlapQueue)
+
+	scheduler := NewScheduler()
+	assert.Nil(t, scheduler.Add(job))
+	assert.Nil(t, scheduler.Start())
+	defer scheduler.Stop(nil)
+
+	// 卡住期间错过的多次执行只排队 1 次，其余记为 Skipped
+	time.Sleep(40 * time.Millisecond)
+	skipped := 0
+	for _, run := range job.History() {
+		if run.Skipped {
+			skipped++
+		}
+	}
+	assert.GreaterOrEqual(t, skipped, 3)
+
+	// 移除后排队的执行被丢弃
+	assert.Nil(t, scheduler.Remove("coalesced"))
+	close(release)
+	waitFor(t, time.Second, func() bool { return job.Running() == 0 })
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestSchedulerOverlapReplace(t *testing.T) {
+	var canceled atomic.Int32
+
+	job := NewScheduledJob("replace", NewEverySchedule(20*time.Millisecond), func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			canceled.Add(1)
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	}).WithOverlap(OverlapReplace)
+
+	scheduler := NewScheduler()
+	assert.Nil(t, scheduler.Add(job))
+	assert.Nil(t, scheduler.Start())
+	time.Sleep(70 * time.Millisecond)
+
+	assert.Equal(t, 1, job.Running())
+	assert.GreaterOrEqual(t, canceled.Load(), int32(2))
+
+	// 超时后取消正在执行的任务
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, scheduler.Stop(ctx), context.DeadlineExceeded)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0, job.Running())
+	for _, run := range job.History() {
+		assert.ErrorIs(t, run.Err, context.Canceled)
+	}
+}
+
+func TestSchedulerStopWaitsAndRecoversPanic(t *testing.T) {
+	var finished atomic.Bool
+
+	scheduler := NewScheduler()
+	slow, err := scheduler.AddFunc("slow", "@every 10ms", func(ctx context.Context) error {
+		time.Sleep(40 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	panicky, err := scheduler.AddFunc("panicky", "@every 10ms", func(ctx context.Context) error {
+		panic("boom")
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, scheduler.Start())
+	time.Sleep(15 * time.Millisecond)
+	assert.Nil(t, scheduler.Stop(nil))
+	assert.True(t, finished.Load())
+	assert.Equal(t, 0, slow.Running())
+
+	run, ok := panicky.LastRun()
+	assert.True(t, ok)
+	var panicError *basic.PanicError
+	assert.ErrorAs(t, run.Err, &panicError)
+}
+
+func TestScheduledJobPreviewAndHistorySize(t *testing.T) {
+	job := NewScheduledJob("daily", MustParseCron("TZ=UTC 0 3 * * *"), nil).WithHistorySize(2)
+
+	times := job.Preview(3)
+	assert.Len(t, times, 3)
+	for i, next := range times {
+		assert.Equal(t, 3, next.Hour())
+		if i > 0 {
+			assert.Equal(t, 24*time.Hour, next.Sub(times[i-1]).Round(time.Hour))
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		job.record(JobRun{Skipped: i%2 == 0})
+	}
+	history := job.History()
+	assert.Len(t, history, 2)
+	assert.False(t, history[0].Skipped)
+	assert.True(t, history[1].Skipped)
+
+	scheduler := NewScheduler()
+	assert.Nil(t, scheduler.Add(job))
+	assert.Nil(t, scheduler.Add(NewScheduledJob("another", NewEverySchedule(time.Hour), nil).WithJitter(time.Minute)))
+	assert.Equal(t, []string{"another", "daily"}, []string{scheduler.Jobs()[0].Name(), scheduler.Jobs()[1].Name()})
+
+	assert.Nil(t, scheduler.Remove("daily"))
+	assert.ErrorIs(t, scheduler.Remove("daily"), ErrScheduledJobNotFound)
+	_, ok := scheduler.Job("daily")
+	assert.False(t, ok)
+}
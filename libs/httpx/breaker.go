@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fasionchan/goutils/jobutils"
+)
+
+// CircuitBreakerTransport 按请求的主机经熔断器发送请求，熔断时直接返回错误而不发送；
+// 5xx 响应计为失败，但仍原样返回给调用方；请求 ctx 已结束时不计为失败
+type CircuitBreakerTransport struct {
+	registry *jobutils.CircuitBreakerRegistry[string]
+	next     http.RoundTripper
+}
+
+// NewCircuitBreakerTransport 创建熔断传输层，next 为 nil 时使用 http.DefaultTransport
+func NewCircuitBreakerTransport(registry *jobutils.CircuitBreakerRegistry[string], next http.RoundTripper) *CircuitBreakerTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &CircuitBreakerTransport{
+		registry: registry,
+		next:     next,
+	}
+}
+
+func (transport *CircuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := transport.registry.Get(req.URL.Host).Allow()
+	if err != nil {
+		// RoundTripper 须关闭请求体，即使未发送
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Host, err)
+	}
+
+	resp, err := transport.next.RoundTrip(req)
+	switch {
+	case req.Context().Err() != nil:
+		// 调用方取消或超时不代表服务端故障
+		done(nil)
+	case err == nil && resp.StatusCode >= http.StatusInternalServerError:
+		done(fmt.Errorf("server error: %s", resp.Status))
+	default:
+		done(err)
+	}
+
+	return resp, err
+}
+
+// WithCircuitBreakers 为客户端启用按主机熔断，保留原有的传输层
+func (client *Client) WithCircuitBreakers(registry *jobutils.CircuitBreakerRegistry[string]) *Client {
+	if client == nil {
+		return nil
+	}
+
+	httpClient := &http.Client{}
+	if client.Client != nil {
+		*httpClient = *client.Client
+	}
+
+	httpClient.Transport = NewCircuitBreakerTransport(registry, httpClient.Transport)
+	client.Client = httpClient
+
+	return client
+}
@@ -0,0 +1,100 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/jobutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	registry := jobutils.NewCircuitBreakerRegistry(func(host string) *jobutils.CircuitBreaker {
+		return jobutils.NewCircuitBreaker(host).WithConsecutiveFailures(2)
+	})
+
+	client := NewBlankHttpClient().WithCircuitBreakers(registry)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if assert.Nil(t, err) {
+			assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+			resp.Body.Close()
+		}
+	}
+
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, jobutils.ErrCircuitOpen)
+	assert.Equal(t, 2, requests)
+}
+
+type closeRecorder struct {
+	closed bool
+}
+
+func (body *closeRecorder) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (body *closeRecorder) Close() error {
+	body.closed = true
+	return nil
+}
+
+func TestCircuitBreakerTransportClosesBody(t *testing.T) {
+	registry := jobutils.NewCircuitBreakerRegistry(func(host string) *jobutils.CircuitBreaker {
+		return jobutils.NewCircuitBreaker(host)
+	})
+	registry.Get("example.invalid").WithConsecutiveFailures(1)
+	done, err := registry.Get("example.invalid").Allow()
+	if assert.Nil(t, err) {
+		done(errors.New("failed"))
+	}
+
+	body := &closeRecorder{}
+	req, err := http.NewRequest(http.MethodPost, "http://example.invalid/", body)
+	assert.Nil(t, err)
+
+	_, err = NewCircuitBreakerTransport(registry, nil).RoundTrip(req)
+	assert.ErrorIs(t, err, jobutils.ErrCircuitOpen)
+	assert.True(t, body.closed)
+}
+
+func TestCircuitBreakerTransportCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	registry := jobutils.NewCircuitBreakerRegistry(func(host string) *jobutils.CircuitBreaker {
+		return jobutils.NewCircuitBreaker(host).WithConsecutiveFailures(1)
+	})
+
+	client := NewBlankHttpClient().WithCircuitBreakers(registry)
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		assert.Nil(t, err)
+
+		_, err = client.Do(req)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	for _, state := range registry.States() {
+		assert.Equal(t, jobutils.CircuitClosed, state)
+	}
+}
@@ -0,0 +1,88 @@
+package stl
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff 为重试等待策略
+type Backoff interface {
+	// NextDelay 返回第 attempt 次重试（从 1 开始）前的等待时间，elapsed 为自首次失败起已过的时间；
+	// ok 为 false 表示放弃重试
+	NextDelay(attempt int, elapsed time.Duration) (delay time.Duration, ok bool)
+}
+
+type BackoffFunc func(attempt int, elapsed time.Duration) (time.Duration, bool)
+
+func (f BackoffFunc) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	return f(attempt, elapsed)
+}
+
+// NewConstantBackoff 每次重试前等待固定时间
+func NewConstantBackoff(delay time.Duration) BackoffFunc {
+	return func(int, time.Duration) (time.Duration, bool) {
+		return delay, true
+	}
+}
+
+// ExponentialBackoff 等待时间从 initial 开始按 multiplier 倍增长，不超过 max；
+// jitter 为随机抖动比例，实际等待时间在 [delay*(1-jitter), delay*(1+jitter)] 内均匀分布
+type ExponentialBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+func NewExponentialBackoff(initial, max time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		initial:    initial,
+		max:        max,
+		multiplier: 2,
+	}
+}
+
+func (backoff *ExponentialBackoff) WithMultiplier(multiplier float64) *ExponentialBackoff {
+	backoff.multiplier = max(multiplier, 1)
+	return backoff
+}
+
+func (backoff *ExponentialBackoff) WithJitter(jitter float64) *ExponentialBackoff {
+	backoff.jitter = min(max(jitter, 0), 1)
+	return backoff
+}
+
+func (backoff *ExponentialBackoff) NextDelay(attempt int, _ time.Duration) (time.Duration, bool) {
+	delay := float64(backoff.initial) * math.Pow(backoff.multiplier, float64(max(attempt, 1)-1))
+	if backoff.max > 0 {
+		delay = min(delay, float64(backoff.max))
+	}
+
+	if backoff.jitter > 0 {
+		delay *= 1 + backoff.jitter*(2*rand.Float64()-1)
+	}
+
+	// max <= 0 且 attempt 很大时 delay 可能为 +Inf，转换前须限制在 time.Duration 范围内
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64, true
+	}
+
+	return time.Duration(delay), true
+}
+
+// MaxElapsedBackoff 在 backoff 的基础上限制总耗时：自首次失败起超过 maxElapsed 后放弃重试
+func MaxElapsedBackoff(backoff Backoff, maxElapsed time.Duration) BackoffFunc {
+	return func(attempt int, elapsed time.Duration) (time.Duration, bool) {
+		if elapsed >= maxElapsed {
+			return 0, false
+		}
+
+		delay, ok := backoff.NextDelay(attempt, elapsed)
+		if !ok {
+			return 0, false
+		}
+
+		return min(delay, maxElapsed-elapsed), true
+	}
+}
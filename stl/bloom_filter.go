@@ -0,0 +1,151 @@
+package stl
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// BloomFilterMaxHashes 为哈希函数个数上限，足以支持 1e-19 的误判率
+const BloomFilterMaxHashes = 64
+
+// BloomFilter 判断键是否可能存在：Contain 返回 false 时一定不存在，返回 true 时有一定误判率。
+type BloomFilter[Key comparable] struct {
+	size   uint64
+	hashes uint64
+	words  []uint64
+	hasher Hasher[Key]
+}
+
+// NewBloomFilter 创建 size 位、每个键使用 hashes 个哈希函数的过滤器，hashes 不超过 BloomFilterMaxHashes
+func NewBloomFilter[Key comparable](size, hashes int) *BloomFilter[Key] {
+	size = max(size, 1)
+	return &BloomFilter[Key]{
+		size:   uint64(size),
+		hashes: uint64(min(max(hashes, 1), BloomFilterMaxHashes)),
+		words:  make([]uint64, (size+63)/64),
+		hasher: DefaultHasher[Key],
+	}
+}
+
+// NewBloomFilterWithEstimates 按预计容纳 n 个键、误判率 falsePositive 计算位数与哈希函数个数
+func NewBloomFilterWithEstimates[Key comparable](n int, falsePositive float64) *BloomFilter[Key] {
+	n = max(n, 1)
+	size := math.Ceil(-float64(n) * math.Log(falsePositive) / (math.Ln2 * math.Ln2))
+	hashes := math.Round(size / float64(n) * math.Ln2)
+	return NewBloomFilter[Key](int(size), int(hashes))
+}
+
+func (filter *BloomFilter[Key]) WithHasher(hasher Hasher[Key]) *BloomFilter[Key] {
+	filter.hasher = hasher
+	return filter
+}
+
+func (filter *BloomFilter[Key]) Size() int {
+	return int(filter.size)
+}
+
+func (filter *BloomFilter[Key]) Hashes() int {
+	return int(filter.hashes)
+}
+
+func (filter *BloomFilter[Key]) positions(key Key, handle func(word int, mask uint64) bool) bool {
+	if filter.hasher == nil {
+		filter.hasher = DefaultHasher[Key]
+	}
+
+	h1, h2 := hashPair(filter.hasher(key))
+	for i := uint64(0); i < filter.hashes; i++ {
+		position := (h1 + i*h2) % filter.size
+		if !handle(int(position/64), 1<<(position%64)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (filter *BloomFilter[Key]) Add(keys ...Key) *BloomFilter[Key] {
+	for _, key := range keys {
+		filter.positions(key, func(word int, mask uint64) bool {
+			filter.words[word] |= mask
+			return true
+		})
+	}
+	return filter
+}
+
+func (filter *BloomFilter[Key]) Contain(key Key) bool {
+	return filter.positions(key, func(word int, mask uint64) bool {
+		return filter.words[word]&mask != 0
+	})
+}
+
+// EstimatedCount 根据置位的比例估计已加入的不同键个数
+func (filter *BloomFilter[Key]) EstimatedCount() uint64 {
+	ones := 0
+	for _, word := range filter.words {
+		ones += bits.OnesCount64(word)
+	}
+
+	if uint64(ones) >= filter.size {
+		return math.MaxUint64
+	}
+
+	m := float64(filter.size)
+	return uint64(-m/float64(filter.hashes)*math.Log(1-float64(ones)/m) + 0.5)
+}
+
+// Merge 合并另一个同参数的过滤器，结果等价于加入两者的键的并集
+func (filter *BloomFilter[Key]) Merge(other *BloomFilter[Key]) error {
+	if filter.size != other.size || filter.hashes != other.hashes {
+		return ErrSketchMismatch
+	}
+
+	for i, word := range other.words {
+		filter.words[i] |= word
+	}
+
+	return nil
+}
+
+func (filter *BloomFilter[Key]) Reset() {
+	clear(filter.words)
+}
+
+const bloomFilterMagic = "blm\x01"
+
+func (filter *BloomFilter[Key]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(bloomFilterMagic)+20+len(filter.words)*8)
+	data = append(data, bloomFilterMagic...)
+	data = binary.AppendUvarint(data, filter.size)
+	data = binary.AppendUvarint(data, filter.hashes)
+	for _, word := range filter.words {
+		data = binary.BigEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 恢复参数与位图，Hasher 保持不变（未设置时为 DefaultHasher）
+func (filter *BloomFilter[Key]) UnmarshalBinary(data []byte) error {
+	reader := newSketchReader(data, bloomFilterMagic)
+
+	size := reader.uvarint()
+	hashes := reader.uvarint()
+	// 先按剩余数据限制 size，以免 size+63 溢出
+	if reader.err != nil || size == 0 || size > uint64(len(reader.data))*8 ||
+		hashes == 0 || hashes > BloomFilterMaxHashes || (size+63)/64*8 != uint64(len(reader.data)) {
+		return ErrSketchCorrupted
+	}
+
+	words := make([]uint64, (size+63)/64)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(reader.bytes(8))
+	}
+
+	if err := reader.finish(); err != nil {
+		return err
+	}
+
+	filter.size, filter.hashes, filter.words = size, hashes, words
+	return nil
+}
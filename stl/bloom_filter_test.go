@@ -0,0 +1,78 @@
+package stl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	filter := NewBloomFilterWithEstimates[string](1000, 0.01)
+	assert.Equal(t, 9586, filter.Size())
+	assert.Equal(t, 7, filter.Hashes())
+
+	for i := 0; i < 1000; i++ {
+		filter.Add(fmt.Sprint("in", i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		assert.True(t, filter.Contain(fmt.Sprint("in", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Contain(fmt.Sprint("out", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 300)
+	assertApproximately(t, 1000, filter.EstimatedCount(), 0.05)
+
+	other := NewBloomFilterWithEstimates[string](1000, 0.01).Add("extra")
+	assert.False(t, filter.Contain("extra"))
+	assert.Nil(t, filter.Merge(other))
+	assert.True(t, filter.Contain("extra"))
+	assert.Equal(t, ErrSketchMismatch, filter.Merge(NewBloomFilter[string](100, 3)))
+
+	data, err := filter.MarshalBinary()
+	assert.Nil(t, err)
+
+	var restored BloomFilter[string]
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.True(t, restored.Contain("extra"))
+	assert.True(t, restored.Contain("in42"))
+	assert.Equal(t, filter.EstimatedCount(), restored.EstimatedCount())
+
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(data[:len(data)-8]))
+
+	// size+63 溢出后位图长度为 0
+	crafted := []byte(bloomFilterMagic)
+	crafted = binary.AppendUvarint(crafted, math.MaxUint64)
+	crafted = binary.AppendUvarint(crafted, 1)
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(crafted))
+
+	crafted = []byte(bloomFilterMagic)
+	crafted = binary.AppendUvarint(crafted, 64)
+	crafted = binary.AppendUvarint(crafted, 1<<40)
+	crafted = append(crafted, make([]byte, 8)...)
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(crafted))
+	assert.True(t, restored.Contain("extra"))
+
+	// 位数少、哈希函数多的过滤器也能还原
+	small := NewBloomFilter[string](8, 10).Add("a")
+	data, err = small.MarshalBinary()
+	assert.Nil(t, err)
+
+	var restoredSmall BloomFilter[string]
+	assert.Nil(t, restoredSmall.UnmarshalBinary(data))
+	assert.Equal(t, 10, restoredSmall.Hashes())
+	assert.True(t, restoredSmall.Contain("a"))
+
+	assert.Equal(t, BloomFilterMaxHashes, NewBloomFilter[string](8, 1000).Hashes())
+
+	filter.Reset()
+	assert.False(t, filter.Contain("extra"))
+}
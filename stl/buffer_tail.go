@@ -0,0 +1,249 @@
+package stl
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// RingBufferTailFrom 为跟随读取的起点
+type RingBufferTailFrom int
+
+const (
+	// TailFromOldest 从缓冲区中最旧的数据开始
+	TailFromOldest RingBufferTailFrom = iota
+	// TailFromNow 只读取此后写入的数据
+	TailFromNow
+)
+
+// TailableRingBuffer 是并发安全的循环缓冲区，保留最后 size 个数据。
+// 读取者可以像 tail -f 一样跟随新写入的数据；写入速度超过读取者、未读数据被覆盖时，
+// 读取者会得知错过的数据个数。
+type TailableRingBuffer[Datas ~[]Data, Data any] struct {
+	mutex   sync.Mutex
+	buffer  Datas
+	size    int
+	total   int64
+	closed  bool
+	changed chan struct{}
+}
+
+// NewTailableRingBuffer 创建保留最后 size 个数据的缓冲区，存储空间随写入量增长
+func NewTailableRingBuffer[Datas ~[]Data, Data any](size int) *TailableRingBuffer[Datas, Data] {
+	return &TailableRingBuffer[Datas, Data]{
+		size:    max(size, 0),
+		changed: make(chan struct{}),
+	}
+}
+
+func (rb *TailableRingBuffer[Datas, Data]) notify() {
+	close(rb.changed)
+	rb.changed = make(chan struct{})
+}
+
+// Write 写入数据并唤醒等待中的读取者，缓冲区关闭后返回 io.ErrClosedPipe
+func (rb *TailableRingBuffer[Datas, Data]) Write(datas Datas) (int, error) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	n := len(datas)
+	if n == 0 {
+		return 0, nil
+	}
+
+	// 超出容量的部分写入即被覆盖，只计数；
+	// 余下数据恰好填满缓冲区，须按序号定位写入，因此先补足存储空间
+	if skip := n - rb.size; skip > 0 {
+		rb.total += int64(skip)
+		datas = datas[skip:]
+		if len(rb.buffer) < rb.size {
+			rb.buffer = append(rb.buffer, make(Datas, rb.size-len(rb.buffer))...)
+		}
+	}
+
+	for len(datas) > 0 {
+		var written int
+		if len(rb.buffer) < rb.size {
+			written = min(rb.size-len(rb.buffer), len(datas))
+			rb.buffer = append(rb.buffer, datas[:written]...)
+		} else {
+			written = copy(rb.buffer[rb.total%int64(rb.size):], datas)
+		}
+
+		rb.total += int64(written)
+		datas = datas[written:]
+	}
+
+	rb.notify()
+	return n, nil
+}
+
+// Close 关闭缓冲区，读取者读完剩余数据后得到 io.EOF
+func (rb *TailableRingBuffer[Datas, Data]) Close() error {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if !rb.closed {
+		rb.closed = true
+		rb.notify()
+	}
+
+	return nil
+}
+
+func (rb *TailableRingBuffer[Datas, Data]) oldest() int64 {
+	return max(rb.total-int64(rb.size), 0)
+}
+
+// copyRange 复制序号在 [from, to) 内的数据，调用方须持有锁并保证范围仍被保留
+func (rb *TailableRingBuffer[Datas, Data]) copyRange(from, to int64) Datas {
+	result := make(Datas, 0, to-from)
+	for from < to {
+		pos := int(from % int64(rb.size))
+		end := min(int64(rb.size-pos), to-from) + int64(pos)
+		result = append(result, rb.buffer[pos:end]...)
+		from += end - int64(pos)
+	}
+	return result
+}
+
+// Datas 返回当前保留的数据
+func (rb *TailableRingBuffer[Datas, Data]) Datas() Datas {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	if rb.total == 0 || rb.size == 0 {
+		return nil
+	}
+
+	return rb.copyRange(rb.oldest(), rb.total)
+}
+
+// TotalWritten 返回总共写入的数据个数，也是下一个数据的序号
+func (rb *TailableRingBuffer[Datas, Data]) TotalWritten() int64 {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	return rb.total
+}
+
+// IsTruncated 返回是否有数据被覆盖
+func (rb *TailableRingBuffer[Datas, Data]) IsTruncated() bool {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	return rb.total > int64(rb.size)
+}
+
+// Tail 创建跟随读取者
+func (rb *TailableRingBuffer[Datas, Data]) Tail(from RingBufferTailFrom) *RingBufferTailer[Datas, Data] {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	pos := rb.oldest()
+	if from == TailFromNow {
+		pos = rb.total
+	}
+
+	return &RingBufferTailer[Datas, Data]{
+		buffer: rb,
+		pos:    pos,
+		done:   make(chan struct{}),
+	}
+}
+
+// RingBufferTailer 跟随读取 TailableRingBuffer，单个读取者不可并发使用
+type RingBufferTailer[Datas ~[]Data, Data any] struct {
+	buffer *TailableRingBuffer[Datas, Data]
+	pos    int64
+	missed int64
+	done   chan struct{}
+	once   sync.Once
+}
+
+// read 读取至多 limit 个数据（limit <= 0 表示不限），没有新数据时阻塞等待
+func (tailer *RingBufferTailer[Datas, Data]) read(ctx context.Context, limit int) (datas Datas, missed int64, err error) {
+	rb := tailer.buffer
+
+	for {
+		rb.mutex.Lock()
+
+		if oldest := rb.oldest(); tailer.pos < oldest {
+			missed += oldest - tailer.pos
+			tailer.missed += oldest - tailer.pos
+			tailer.pos = oldest
+		}
+
+		if tailer.pos < rb.total {
+			to := rb.total
+			if limit > 0 {
+				to = min(to, tailer.pos+int64(limit))
+			}
+
+			datas = rb.copyRange(tailer.pos, to)
+			tailer.pos = to
+			rb.mutex.Unlock()
+			return datas, missed, nil
+		}
+
+		closed, changed := rb.closed, rb.changed
+		rb.mutex.Unlock()
+
+		if closed {
+			return nil, missed, io.EOF
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, missed, ctx.Err()
+		case <-tailer.done:
+			return nil, missed, io.EOF
+		case <-changed:
+		}
+	}
+}
+
+// Next 返回自上次读取以来的全部新数据，没有新数据时阻塞等待。
+// missed 为本次跳过的、已被覆盖的数据个数；缓冲区或读取者关闭且数据读完时返回 io.EOF。
+func (tailer *RingBufferTailer[Datas, Data]) Next(ctx context.Context) (datas Datas, missed int64, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tailer.read(ctx, 0)
+}
+
+// Read 实现 io.Reader 风格的阻塞读取，跳过的数据个数累计到 Missed
+func (tailer *RingBufferTailer[Datas, Data]) Read(datas Datas) (int, error) {
+	if len(datas) == 0 {
+		return 0, nil
+	}
+
+	result, _, err := tailer.read(context.Background(), len(datas))
+	return copy(datas, result), err
+}
+
+// Missed 返回累计跳过的数据个数
+func (tailer *RingBufferTailer[Datas, Data]) Missed() int64 {
+	rb := tailer.buffer
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	return tailer.missed
+}
+
+// Position 返回下一个待读数据的序号
+func (tailer *RingBufferTailer[Datas, Data]) Position() int64 {
+	rb := tailer.buffer
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	return tailer.pos
+}
+
+// Close 关闭读取者，阻塞中的读取返回 io.EOF
+func (tailer *RingBufferTailer[Datas, Data]) Close() error {
+	tailer.once.Do(func() {
+		close(tailer.done)
+	})
+	return nil
+}
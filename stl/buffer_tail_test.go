@@ -0,0 +1,170 @@
+package stl
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailableRingBuffer(t *testing.T) {
+	rb := NewTailableRingBuffer[[]byte](5)
+	assert.Nil(t, rb.Datas())
+
+	oldest := rb.Tail(TailFromOldest)
+
+	n, err := rb.Write([]byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	now := rb.Tail(TailFromNow)
+
+	datas, missed, err := oldest.Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", string(datas))
+	assert.Equal(t, int64(0), missed)
+
+	rb.Write([]byte("defgh"))
+	assert.Equal(t, "defgh", string(rb.Datas()))
+	assert.Equal(t, int64(8), rb.TotalWritten())
+	assert.True(t, rb.IsTruncated())
+
+	datas, missed, err = now.Next(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "defgh", string(datas))
+	assert.Equal(t, int64(0), missed)
+
+	rb.Write([]byte("0123456789"))
+	assert.Equal(t, "56789", string(rb.Datas()))
+
+	datas, missed, err = oldest.Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "56789", string(datas))
+	assert.Equal(t, int64(10), missed)
+	assert.Equal(t, int64(10), oldest.Missed())
+	assert.Equal(t, int64(18), oldest.Position())
+
+	buf := make([]byte, 2)
+	n, err = now.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "56", string(buf[:n]))
+	assert.Equal(t, int64(5), now.Missed())
+
+	rb.Close()
+	n, err = now.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "78", string(buf[:n]))
+
+	rest, err := io.ReadAll(readerFunc(now.Read))
+	assert.Nil(t, err)
+	assert.Equal(t, "9", string(rest))
+
+	_, err = rb.Write([]byte("x"))
+	assert.Equal(t, io.ErrClosedPipe, err)
+
+	late := rb.Tail(TailFromOldest)
+	datas, _, err = late.Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "56789", string(datas))
+	_, _, err = late.Next(context.Background())
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestTailableRingBufferOversizedWrite(t *testing.T) {
+	empty := NewTailableRingBuffer[[]byte](4)
+	oldest := empty.Tail(TailFromOldest)
+	empty.Write([]byte("abcdef"))
+	assert.Equal(t, "cdef", string(empty.Datas()))
+
+	datas, missed, err := oldest.Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "cdef", string(datas))
+	assert.Equal(t, int64(2), missed)
+
+	partial := NewTailableRingBuffer[[]byte](4)
+	partial.Write([]byte("a"))
+	partial.Write([]byte("bcdef"))
+	assert.Equal(t, "cdef", string(partial.Datas()))
+	assert.Equal(t, int64(6), partial.TotalWritten())
+
+	partial.Write([]byte("gh"))
+	assert.Equal(t, "efgh", string(partial.Datas()))
+
+	datas, _, err = partial.Tail(TailFromOldest).Next(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "efgh", string(datas))
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestTailableRingBufferFollow(t *testing.T) {
+	rb := NewTailableRingBuffer[[]string](100)
+	tailer := rb.Tail(TailFromNow)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, _, err := tailer.Next(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	const writers, lines = 4, 250
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				rb.Write([]string{"line"})
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		rb.Close()
+	}()
+
+	received := int64(0)
+	for {
+		datas, missed, err := tailer.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		received += int64(len(datas)) + missed
+	}
+
+	assert.Equal(t, int64(writers*lines), received)
+	assert.Equal(t, int64(writers*lines), rb.TotalWritten())
+}
+
+func TestRingBufferTailerClose(t *testing.T) {
+	rb := NewTailableRingBuffer[[]int](3)
+	tailer := rb.Tail(TailFromNow)
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		tailer.Close()
+	}()
+
+	_, _, err := tailer.Next(context.Background())
+	assert.Equal(t, io.EOF, err)
+
+	empty := NewTailableRingBuffer[[]int](0)
+	n, err := empty.Write([]int{1, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Nil(t, empty.Datas())
+	empty.Close()
+
+	_, missed, err := empty.Tail(TailFromOldest).Next(context.Background())
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, int64(0), missed)
+}
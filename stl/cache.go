@@ -0,0 +1,618 @@
+package stl
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+)
+
+type CacheEvictionPolicy int
+
+const (
+	CacheEvictionLRU CacheEvictionPolicy = iota // 淘汰最久未访问的条目
+	CacheEvictionLFU                            // 淘汰访问次数最少的条目，次数相同时淘汰最久未访问的
+)
+
+func (policy CacheEvictionPolicy) String() string {
+	switch policy {
+	case CacheEvictionLRU:
+		return "LRU"
+	case CacheEvictionLFU:
+		return "LFU"
+	default:
+		return "Unknown"
+	}
+}
+
+type CacheEvictionReason int
+
+const (
+	CacheEvictionReasonCapacity CacheEvictionReason = iota // 超出条目数或成本上限
+	CacheEvictionReasonExpired                             // 超过存活时间
+	CacheEvictionReasonDeleted                             // 主动删除或清空
+)
+
+func (reason CacheEvictionReason) String() string {
+	switch reason {
+	case CacheEvictionReasonCapacity:
+		return "Capacity"
+	case CacheEvictionReasonExpired:
+		return "Expired"
+	case CacheEvictionReasonDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+type CacheEvictedCallback[Key comparable, Value any] func(key Key, value Value, reason CacheEvictionReason)
+
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // 仅统计容量淘汰和过期淘汰，不含主动删除
+}
+
+func (stats CacheStats) Requests() uint64 {
+	return stats.Hits + stats.Misses
+}
+
+func (stats CacheStats) HitRatio() float64 {
+	requests := stats.Requests()
+	if requests == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(requests)
+}
+
+type cacheEntry[Key comparable, Value any] struct {
+	key       Key
+	value     Value
+	cost      int64
+	expiresAt time.Time
+	freq      int
+	element   *list.Element
+}
+
+func (entry *cacheEntry[Key, Value]) expired(now time.Time) bool {
+	return !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt)
+}
+
+type cacheCall[Value any] struct {
+	done  chan struct{}
+	value Value
+	err   error
+}
+
+type cacheEviction[Key comparable, Value any] struct {
+	key    Key
+	value  Value
+	reason CacheEvictionReason
+}
+
+// Cache 是并发安全的有界缓存，支持条目数上限、成本上限、按条目 TTL 以及 LRU/LFU 淘汰策略。
+// 淘汰回调在锁外同步调用，回调中可以再次访问缓存。
+type Cache[Key comparable, Value any] struct {
+	policy     CacheEvictionPolicy
+	maxEntries int
+	maxCost    int64
+	ttl        time.Duration
+	costFunc   func(Key, Value) int64
+	createFunc func(context.Context, Key) (Value, error)
+	callbacks  []CacheEvictedCallback[Key, Value]
+
+	entries map[Key]*cacheEntry[Key, Value]
+	recency *list.List         // LRU：表头为最近访问
+	freqs   map[int]*list.List // LFU：按访问次数分桶，桶内表头为最近访问
+	minFreq int
+	cost    int64
+	stats   CacheStats
+
+	creating map[Key]*cacheCall[Value] // 正在执行 LoadOrCreate 创建的键
+
+	mutex sync.Mutex
+}
+
+// NewCache 创建 LRU 缓存；maxEntries <= 0 表示不限条目数。
+func NewCache[Key comparable, Value any](maxEntries int) *Cache[Key, Value] {
+	return &Cache[Key, Value]{
+		maxEntries: maxEntries,
+		entries:    make(map[Key]*cacheEntry[Key, Value]),
+		recency:    list.New(),
+		freqs:      make(map[int]*list.List),
+		creating:   make(map[Key]*cacheCall[Value]),
+	}
+}
+
+func NewLFUCache[Key comparable, Value any](maxEntries int) *Cache[Key, Value] {
+	return NewCache[Key, Value](maxEntries).WithPolicy(CacheEvictionLFU)
+}
+
+// WithPolicy 切换淘汰策略，应在写入数据前调用。
+func (c *Cache[Key, Value]) WithPolicy(policy CacheEvictionPolicy) *Cache[Key, Value] {
+	c.policy = policy
+	return c
+}
+
+func (c *Cache[Key, Value]) WithMaxEntries(maxEntries int) *Cache[Key, Value] {
+	c.maxEntries = maxEntries
+	return c
+}
+
+// WithMaxCost 设置总成本上限；maxCost <= 0 表示不限。未设置 costFunc 时每个条目成本为 1。
+func (c *Cache[Key, Value]) WithMaxCost(maxCost int64) *Cache[Key, Value] {
+	c.maxCost = maxCost
+	return c
+}
+
+func (c *Cache[Key, Value]) WithCostFunc(costFunc func(Key, Value) int64) *Cache[Key, Value] {
+	c.costFunc = costFunc
+	return c
+}
+
+// WithTTL 设置默认存活时间；ttl <= 0 表示永不过期。
+func (c *Cache[Key, Value]) WithTTL(ttl time.Duration) *Cache[Key, Value] {
+	c.ttl = ttl
+	return c
+}
+
+// WithCreateFunc 设置 LoadOrCreate 未指定 create 时使用的默认构造函数。
+func (c *Cache[Key, Value]) WithCreateFunc(createFunc func(context.Context, Key) (Value, error)) *Cache[Key, Value] {
+	c.createFunc = createFunc
+	return c
+}
+
+func (c *Cache[Key, Value]) WithEvictedCallback(callbacks ...CacheEvictedCallback[Key, Value]) *Cache[Key, Value] {
+	c.callbacks = append(c.callbacks, callbacks...)
+	return c
+}
+
+func (c *Cache[Key, Value]) Policy() CacheEvictionPolicy {
+	return c.policy
+}
+
+func (c *Cache[Key, Value]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.entries)
+}
+
+func (c *Cache[Key, Value]) Empty() bool {
+	return c.Len() == 0
+}
+
+func (c *Cache[Key, Value]) Cost() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.cost
+}
+
+func (c *Cache[Key, Value]) Stats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+func (c *Cache[Key, Value]) ResetStats() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats = CacheStats{}
+}
+
+// Keys 返回未过期的键，按淘汰顺序从后往前排列（最不容易被淘汰的在前）。
+func (c *Cache[Key, Value]) Keys() []Key {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	keys := make([]Key, 0, len(c.entries))
+	c.walk(func(entry *cacheEntry[Key, Value]) {
+		if !entry.expired(now) {
+			keys = append(keys, entry.key)
+		}
+	})
+
+	return keys
+}
+
+func (c *Cache[Key, Value]) Values() []Value {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	values := make([]Value, 0, len(c.entries))
+	c.walk(func(entry *cacheEntry[Key, Value]) {
+		if !entry.expired(now) {
+			values = append(values, entry.value)
+		}
+	})
+
+	return values
+}
+
+func (c *Cache[Key, Value]) Contains(key Key) bool {
+	_, ok := c.Peek(key)
+	return ok
+}
+
+func (c *Cache[Key, Value]) Get(key Key) Value {
+	value, _ := c.Load(key)
+	return value
+}
+
+// Load 查询键值，命中时更新访问记录并计入命中统计。
+func (c *Cache[Key, Value]) Load(key Key) (value Value, ok bool) {
+	c.mutex.Lock()
+	value, ok, evictions := c.load(key, time.Now())
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+	return
+}
+
+// Peek 查询键值，但不更新访问记录和统计。
+func (c *Cache[Key, Value]) Peek(key Key) (value Value, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return value, false
+	}
+
+	return entry.value, true
+}
+
+func (c *Cache[Key, Value]) Store(key Key, value Value) {
+	c.StorePro(key, value, -1, c.ttl)
+}
+
+func (c *Cache[Key, Value]) StoreWithTTL(key Key, value Value, ttl time.Duration) {
+	c.StorePro(key, value, -1, ttl)
+}
+
+// StorePro 写入键值；cost < 0 时由 costFunc 计算，ttl <= 0 表示永不过期。
+// 单个条目成本超过成本上限时不会被写入，同键的旧条目随之淘汰。
+func (c *Cache[Key, Value]) StorePro(key Key, value Value, cost int64, ttl time.Duration) {
+	c.mutex.Lock()
+	evictions := c.store(key, value, cost, ttl, time.Now())
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+}
+
+func (c *Cache[Key, Value]) LoadOrStore(key Key, value Value) (Value, bool) {
+	c.mutex.Lock()
+
+	now := time.Now()
+	if loaded, ok, evictions := c.load(key, now); ok {
+		c.mutex.Unlock()
+		c.notify(evictions)
+		return loaded, true
+	}
+
+	evictions := c.store(key, value, -1, c.ttl, now)
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+	return value, false
+}
+
+// LoadOrCreate 与 SyncMap.LoadOrCreate 语义一致，可作为 CachedDataFetcherStoreMap 的底层存储。
+// create 在锁外执行，可以再次访问缓存；同一个键同时只有一个调用方执行创建，其他调用方等待其结果，
+// ctx 结束时等待的调用方返回 ctx.Err()。创建失败或 panic 时不保存，等待的调用方得到相同的错误。
+func (c *Cache[Key, Value]) LoadOrCreate(ctx context.Context, key Key, create func(ctx context.Context, key Key) (Value, error)) (value Value, loaded bool, err error) {
+	c.mutex.Lock()
+
+	value, loaded, evictions := c.load(key, time.Now())
+	if loaded {
+		c.mutex.Unlock()
+		c.notify(evictions)
+		return
+	}
+
+	if call, ok := c.creating[key]; ok {
+		c.mutex.Unlock()
+		c.notify(evictions)
+
+		ctx = chanContext(ctx)
+		select {
+		case <-ctx.Done():
+			return value, false, ctx.Err()
+		case <-call.done:
+			return call.value, call.err == nil, call.err
+		}
+	}
+
+	call := &cacheCall[Value]{done: make(chan struct{})}
+	c.creating[key] = call
+	c.mutex.Unlock()
+	c.notify(evictions)
+
+	if create == nil {
+		create = c.createFunc
+	}
+
+	defer func() {
+		var evictions []cacheEviction[Key, Value]
+
+		c.mutex.Lock()
+		delete(c.creating, key)
+		if err == nil {
+			evictions = c.store(key, value, -1, c.ttl, time.Now())
+		}
+		c.mutex.Unlock()
+
+		call.value, call.err = value, err
+		close(call.done)
+
+		c.notify(evictions)
+	}()
+
+	// create panic 时转为错误，避免等待的调用方永远阻塞
+	defer basic.RecoverPanic(&err)
+
+	if create == nil {
+		return
+	}
+
+	value, err = create(ctx, key)
+	return
+}
+
+func (c *Cache[Key, Value]) Delete(key Key) {
+	c.LoadAndDelete(key)
+}
+
+func (c *Cache[Key, Value]) LoadAndDelete(key Key) (value Value, ok bool) {
+	c.mutex.Lock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		c.mutex.Unlock()
+		return
+	}
+
+	if entry.expired(time.Now()) {
+		eviction := c.evict(entry, CacheEvictionReasonExpired)
+		c.mutex.Unlock()
+
+		c.notify([]cacheEviction[Key, Value]{eviction})
+		return value, false
+	}
+
+	c.remove(entry)
+	c.mutex.Unlock()
+
+	c.notify([]cacheEviction[Key, Value]{{key: entry.key, value: entry.value, reason: CacheEvictionReasonDeleted}})
+	return entry.value, true
+}
+
+func (c *Cache[Key, Value]) Clear() {
+	c.mutex.Lock()
+
+	evictions := make([]cacheEviction[Key, Value], 0, len(c.entries))
+	for _, entry := range c.entries {
+		evictions = append(evictions, cacheEviction[Key, Value]{key: entry.key, value: entry.value, reason: CacheEvictionReasonDeleted})
+	}
+
+	c.entries = make(map[Key]*cacheEntry[Key, Value])
+	c.recency.Init()
+	c.freqs = make(map[int]*list.List)
+	c.minFreq = 0
+	c.cost = 0
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+}
+
+// PurgeExpired 清理全部过期条目，返回清理个数；过期条目平时只在访问时惰性清理。
+func (c *Cache[Key, Value]) PurgeExpired() int {
+	c.mutex.Lock()
+
+	now := time.Now()
+	var evictions []cacheEviction[Key, Value]
+	for _, entry := range c.entries {
+		if entry.expired(now) {
+			evictions = append(evictions, c.evict(entry, CacheEvictionReasonExpired))
+		}
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+	return len(evictions)
+}
+
+func (c *Cache[Key, Value]) load(key Key, now time.Time) (value Value, ok bool, evictions []cacheEviction[Key, Value]) {
+	entry, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return
+	}
+
+	if entry.expired(now) {
+		c.stats.Misses++
+		evictions = append(evictions, c.evict(entry, CacheEvictionReasonExpired))
+		return value, false, evictions
+	}
+
+	c.stats.Hits++
+	c.touch(entry)
+
+	return entry.value, true, nil
+}
+
+func (c *Cache[Key, Value]) store(key Key, value Value, cost int64, ttl time.Duration, now time.Time) (evictions []cacheEviction[Key, Value]) {
+	if cost < 0 {
+		cost = 1
+		if c.costFunc != nil {
+			cost = c.costFunc(key, value)
+		}
+	}
+
+	old, exists := c.entries[key]
+
+	// 新值放不下，同键的旧值也不再有效，按超出容量淘汰
+	if c.maxCost > 0 && cost > c.maxCost {
+		if exists {
+			evictions = append(evictions, c.evict(old, CacheEvictionReasonCapacity))
+		}
+		return
+	}
+
+	freq := 1
+	if exists {
+		freq = old.freq + 1
+		c.remove(old)
+	}
+
+	for len(c.entries) > 0 && c.overflow(1, cost) {
+		victim := c.victim()
+		reason := CacheEvictionReasonCapacity
+		if victim.expired(now) {
+			reason = CacheEvictionReasonExpired
+		}
+		evictions = append(evictions, c.evict(victim, reason))
+	}
+
+	entry := &cacheEntry[Key, Value]{
+		key:   key,
+		value: value,
+		cost:  cost,
+		freq:  freq,
+	}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+
+	c.insert(entry)
+
+	return
+}
+
+func (c *Cache[Key, Value]) overflow(entries int, cost int64) bool {
+	if c.maxEntries > 0 && len(c.entries)+entries > c.maxEntries {
+		return true
+	}
+
+	if c.maxCost > 0 && c.cost+cost > c.maxCost {
+		return true
+	}
+
+	return false
+}
+
+func (c *Cache[Key, Value]) evict(entry *cacheEntry[Key, Value], reason CacheEvictionReason) cacheEviction[Key, Value] {
+	c.remove(entry)
+	c.stats.Evictions++
+	return cacheEviction[Key, Value]{key: entry.key, value: entry.value, reason: reason}
+}
+
+func (c *Cache[Key, Value]) notify(evictions []cacheEviction[Key, Value]) {
+	for _, eviction := range evictions {
+		for _, callback := range c.callbacks {
+			callback(eviction.key, eviction.value, eviction.reason)
+		}
+	}
+}
+
+func (c *Cache[Key, Value]) listOf(entry *cacheEntry[Key, Value]) *list.List {
+	if c.policy != CacheEvictionLFU {
+		return c.recency
+	}
+
+	bucket, ok := c.freqs[entry.freq]
+	if !ok {
+		bucket = list.New()
+		c.freqs[entry.freq] = bucket
+	}
+
+	return bucket
+}
+
+func (c *Cache[Key, Value]) insert(entry *cacheEntry[Key, Value]) {
+	if len(c.entries) == 0 || entry.freq < c.minFreq {
+		c.minFreq = entry.freq
+	}
+
+	entry.element = c.listOf(entry).PushFront(entry)
+	c.entries[entry.key] = entry
+	c.cost += entry.cost
+}
+
+func (c *Cache[Key, Value]) unlink(entry *cacheEntry[Key, Value]) {
+	bucket := c.listOf(entry)
+	bucket.Remove(entry.element)
+	entry.element = nil
+
+	if c.policy == CacheEvictionLFU && bucket.Len() == 0 {
+		delete(c.freqs, entry.freq)
+		if c.minFreq == entry.freq {
+			c.minFreq++
+		}
+	}
+}
+
+func (c *Cache[Key, Value]) remove(entry *cacheEntry[Key, Value]) {
+	c.unlink(entry)
+	delete(c.entries, entry.key)
+	c.cost -= entry.cost
+}
+
+func (c *Cache[Key, Value]) touch(entry *cacheEntry[Key, Value]) {
+	if c.policy != CacheEvictionLFU {
+		c.recency.MoveToFront(entry.element)
+		return
+	}
+
+	c.unlink(entry)
+	entry.freq++
+	entry.element = c.listOf(entry).PushFront(entry)
+}
+
+func (c *Cache[Key, Value]) victim() *cacheEntry[Key, Value] {
+	if c.policy != CacheEvictionLFU {
+		return c.recency.Back().Value.(*cacheEntry[Key, Value])
+	}
+
+	bucket, ok := c.freqs[c.minFreq]
+	if !ok {
+		// 删除条目后 minFreq 可能失效，重新计算
+		first := true
+		for freq := range c.freqs {
+			if first || freq < c.minFreq {
+				c.minFreq = freq
+				first = false
+			}
+		}
+		bucket = c.freqs[c.minFreq]
+	}
+
+	return bucket.Back().Value.(*cacheEntry[Key, Value])
+}
+
+func (c *Cache[Key, Value]) walk(f func(*cacheEntry[Key, Value])) {
+	if c.policy != CacheEvictionLFU {
+		for element := c.recency.Front(); element != nil; element = element.Next() {
+			f(element.Value.(*cacheEntry[Key, Value]))
+		}
+		return
+	}
+
+	freqs := MapKeys(c.freqs)
+	Sort(freqs, Greater[int])
+	for _, freq := range freqs {
+		for element := c.freqs[freq].Front(); element != nil; element = element.Next() {
+			f(element.Value.(*cacheEntry[Key, Value]))
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package stl
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheLRUEviction(t *testing.T) {
+	var evicted []string
+	cache := NewCache[string, int](2).WithEvictedCallback(func(key string, value int, reason CacheEvictionReason) {
+		assert.Equal(t, CacheEvictionReasonCapacity, reason)
+		evicted = append(evicted, key)
+	})
+
+	cache.Store("a", 1)
+	cache.Store("b", 2)
+
+	value, ok := cache.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	cache.Store("c", 3)
+	assert.Equal(t, []string{"b"}, evicted)
+	assert.False(t, cache.Contains("b"))
+	assert.Equal(t, []string{"c", "a"}, cache.Keys())
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(0), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+}
+
+func TestCacheLFUEviction(t *testing.T) {
+	cache := NewLFUCache[string, int](2)
+
+	cache.Store("a", 1)
+	cache.Store("b", 2)
+	cache.Load("a")
+	cache.Load("a")
+	cache.Load("b")
+
+	cache.Store("c", 3)
+	assert.True(t, cache.Contains("a"))
+	assert.False(t, cache.Contains("b"))
+	assert.True(t, cache.Contains("c"))
+
+	cache.Store("d", 4)
+	assert.True(t, cache.Contains("a"))
+	assert.False(t, cache.Contains("c"))
+	assert.Equal(t, []string{"a", "d"}, cache.Keys())
+}
+
+func TestCacheMaxCost(t *testing.T) {
+	cache := NewCache[string, string](0).
+		WithMaxCost(10).
+		WithCostFunc(func(key string, value string) int64 {
+			return int64(len(value))
+		})
+
+	cache.Store("a", "12345")
+	cache.Store("b", "1234")
+	assert.Equal(t, int64(9), cache.Cost())
+
+	cache.Store("c", "12")
+	assert.False(t, cache.Contains("a"))
+	assert.Equal(t, int64(6), cache.Cost())
+
+	cache.Store("huge", "12345678901")
+	assert.False(t, cache.Contains("huge"))
+	assert.Equal(t, 2, cache.Len())
+
+	var evicted []string
+	cache.WithEvictedCallback(func(key string, value string, reason CacheEvictionReason) {
+		assert.Equal(t, CacheEvictionReasonCapacity, reason)
+		evicted = append(evicted, key+"="+value)
+	})
+
+	evictions := cache.Stats().Evictions
+	cache.Store("b", "12345678901")
+	assert.False(t, cache.Contains("b"))
+	assert.Equal(t, []string{"b=1234"}, evicted)
+	assert.Equal(t, evictions+1, cache.Stats().Evictions)
+	assert.Equal(t, int64(2), cache.Cost())
+}
+
+func TestCacheTTL(t *testing.T) {
+	var reasons []CacheEvictionReason
+	cache := NewCache[string, int](0).
+		WithTTL(time.Millisecond * 50).
+		WithEvictedCallback(func(key string, value int, reason CacheEvictionReason) {
+			reasons = append(reasons, reason)
+		})
+
+	cache.Store("a", 1)
+	cache.StoreWithTTL("b", 2, time.Hour)
+	cache.StorePro("c", 3, 1, 0)
+
+	time.Sleep(time.Millisecond * 100)
+
+	_, ok := cache.Load("a")
+	assert.False(t, ok)
+	assert.True(t, cache.Contains("b"))
+	assert.True(t, cache.Contains("c"))
+	assert.Equal(t, []CacheEvictionReason{CacheEvictionReasonExpired}, reasons)
+
+	cache.StoreWithTTL("d", 4, time.Millisecond)
+	time.Sleep(time.Millisecond * 10)
+	assert.Equal(t, 1, cache.PurgeExpired())
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Delete("b")
+	assert.Equal(t, CacheEvictionReasonDeleted, reasons[len(reasons)-1])
+}
+
+func TestCacheLoadOrCreate(t *testing.T) {
+	cache := NewCache[int, string](0)
+
+	created := 0
+	create := func(ctx context.Context, key int) (string, error) {
+		created++
+		return "value", nil
+	}
+
+	value, loaded, err := cache.LoadOrCreate(context.Background(), 1, create)
+	assert.Nil(t, err)
+	assert.False(t, loaded)
+	assert.Equal(t, "value", value)
+
+	value, loaded, err = cache.LoadOrCreate(context.Background(), 1, create)
+	assert.Nil(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, "value", value)
+	assert.Equal(t, 1, created)
+
+	value, loaded = cache.LoadOrStore(2, "other")
+	assert.False(t, loaded)
+	assert.Equal(t, "other", value)
+
+	value, loaded = cache.LoadOrStore(2, "another")
+	assert.True(t, loaded)
+	assert.Equal(t, "other", value)
+}
+
+func TestCacheLoadOrCreateOutsideLock(t *testing.T) {
+	cache := NewCache[int, string](0)
+
+	// create panic 后锁已释放，且不保存
+	_, loaded, err := cache.LoadOrCreate(context.Background(), 1, func(ctx context.Context, key int) (string, error) {
+		panic("boom")
+	})
+	var panicError *basic.PanicError
+	assert.ErrorAs(t, err, &panicError)
+	assert.False(t, loaded)
+	assert.Equal(t, 0, cache.Len())
+
+	// create 中可以再次访问缓存
+	value, _, err := cache.LoadOrCreate(context.Background(), 1, func(ctx context.Context, key int) (string, error) {
+		cache.Store(2, "two")
+		other, _ := cache.Load(2)
+		return other + "+one", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "two+one", value)
+
+	// 同一个键并发创建只执行一次
+	var created atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _, err := cache.LoadOrCreate(context.Background(), 3, func(ctx context.Context, key int) (string, error) {
+				created.Add(1)
+				<-release
+				return "three", nil
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "three", value)
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), created.Load())
+
+	// 等待方的 ctx 结束时返回 ctx.Err()
+	block := make(chan struct{})
+	defer close(block)
+	go cache.LoadOrCreate(context.Background(), 4, func(ctx context.Context, key int) (string, error) {
+		<-block
+		return "four", nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = cache.LoadOrCreate(ctx, 4, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	cache := NewLFUCache[int, int](64)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := (i*1000 + j) % 128
+				cache.Store(key, j)
+				cache.Load(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 64)
+}
+
+func TestCachedDataFetcherMapWithCache(t *testing.T) {
+	var evicted []int
+	cache := NewCache[int, *CachedDataFetcher[int]](2).
+		WithEvictedCallback(func(key int, _ *CachedDataFetcher[int], _ CacheEvictionReason) {
+			evicted = append(evicted, key)
+		})
+
+	m := NewCachedDataFetcherMapWithStore(cache, func(ctx context.Context, key int, sinceTime time.Time) (int, time.Time, error) {
+		return key * 10, time.Now(), nil
+	})
+	assert.Equal(t, CachedDataFetcherStore[int, int](cache), m.FetcherStore())
+
+	for _, key := range []int{1, 2, 3} {
+		data, err := m.FetchLite(context.Background(), key)
+		assert.Nil(t, err)
+		assert.Equal(t, key*10, data)
+	}
+
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, []int{1}, evicted)
+
+	// 原有的 CachedDataFetcherMap 仍是 SyncMap
+	legacy := NewCachedDataFetcherMap(0, func(ctx context.Context, key int, sinceTime time.Time) (int, time.Time, error) {
+		return key, time.Now(), nil
+	})
+	data, err := legacy.FetchLite(context.Background(), 7)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, data)
+	assert.Equal(t, 1, legacy.SyncMapping().Len())
+}
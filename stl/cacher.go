@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/fasionchan/goutils/basic"
 	"go.uber.org/zap"
 )
 
@@ -100,17 +101,59 @@ func (timed *TimedValue[Value]) ValueAndTime() (value Value, t time.Time) {
 	return timed.value, timed.t
 }
 
+// cachedDataFetcherFlight 为一次进行中的拉取，并发的拉取请求共享其结果（singleflight）
+type cachedDataFetcherFlight[Data any] struct {
+	since   time.Time
+	started time.Time
+	equal   func(a, b Data) bool // fetcher 未设置比较函数时本次拉取使用的比较函数
+
+	done chan struct{}
+	data Data
+	t    time.Time
+	err  error
+}
+
+// covers 判断该拉取能否满足 requested 时发起、要求数据晚于 since 的显式刷新
+func (flight *cachedDataFetcherFlight[Data]) covers(since, requested time.Time) bool {
+	return !flight.started.Before(requested) && !flight.since.Before(since)
+}
+
+func (flight *cachedDataFetcherFlight[Data]) wait(ctx context.Context) (data Data, t time.Time, err error) {
+	if ctx == nil {
+		<-flight.done
+		return flight.data, flight.t, flight.err
+	}
+
+	select {
+	case <-flight.done:
+		return flight.data, flight.t, flight.err
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	}
+}
+
 type CachedDataFetcher[Data any] struct {
 	*zap.Logger
 
 	fetcher         CachedDataFetcherFetchFunc[Data]
 	expiresDuration time.Duration
+	// 数据过期但未超过调用方给出的 fallbackDuration 时，直接返回并在后台刷新
+	staleWhileRevalidate bool
 
 	callbacks CachedDataFetcherCallbacks[Data]
+	equal     func(a, b Data) bool // 设置后，数据未变化时不触发回调
+
+	merge              func(old, delta Data) Data // 设置后按增量拉取，并将增量合并到缓存数据
+	fullReloadInterval time.Duration              // 增量模式下全量重新加载的间隔，用于消除累积误差
+	fullReloadTime     time.Time
+	fullReloadPending  bool
 
-	data *TimedValue[Data]
+	data   *TimedValue[Data]
+	flight *cachedDataFetcherFlight[Data]
 
-	mutex sync.Mutex
+	mutex     sync.Mutex
+	dataMutex sync.RWMutex // 仅保护 data，读取缓存无需等待 mutex
 }
 
 func NewCachedDataFetcher[Data any](fetcher CachedDataFetcherFetchFunc[Data]) *CachedDataFetcher[Data] {
@@ -141,11 +184,17 @@ func (fetcher *CachedDataFetcher[Data]) Dup() *CachedDataFetcher[Data] {
 	return &CachedDataFetcher[Data]{
 		fetcher:         fetcher.fetcher,
 		expiresDuration: fetcher.expiresDuration,
+		equal:           fetcher.equal,
+
+		staleWhileRevalidate: fetcher.staleWhileRevalidate,
+
+		merge:              fetcher.merge,
+		fullReloadInterval: fetcher.fullReloadInterval,
 	}
 }
 
 func (fetcher *CachedDataFetcher[Data]) WithCachedDataPurged() *CachedDataFetcher[Data] {
-	fetcher.data = nil
+	fetcher.setCached(nil)
 	return fetcher
 }
 
@@ -197,6 +246,37 @@ func (fetcher *CachedDataFetcher[Data]) WithExpiresDuration(duration time.Durati
 	return fetcher
 }
 
+// WithStaleWhileRevalidate 开启 stale-while-revalidate：FetchWithExpiresPro 发现数据过期，
+// 但拉取时间距今不超过其 fallbackDuration 时，直接返回缓存数据，同时在后台发起一次刷新；
+// 超过 fallbackDuration 后仍同步拉取。未给出 fallbackDuration 的 Fetch* 不受影响。
+func (fetcher *CachedDataFetcher[Data]) WithStaleWhileRevalidate(enabled bool) *CachedDataFetcher[Data] {
+	fetcher.staleWhileRevalidate = enabled
+	return fetcher
+}
+
+// WithEqualFunc 设置数据比较函数，刷新得到的数据与缓存相同时不再触发回调
+func (fetcher *CachedDataFetcher[Data]) WithEqualFunc(equal func(a, b Data) bool) *CachedDataFetcher[Data] {
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+
+	fetcher.equal = equal
+	return fetcher
+}
+
+// WithMergeFunc 开启增量模式：拉取函数收到的 sinceTime 为当前缓存数据的时间，只需返回此后的变更，
+// 由 merge 合并到缓存数据上；没有缓存数据或需要全量重新加载时 sinceTime 为零值，返回结果直接替换缓存。
+// merge 不应原地修改 old，读取方可能仍持有它。
+func (fetcher *CachedDataFetcher[Data]) WithMergeFunc(merge func(old, delta Data) Data) *CachedDataFetcher[Data] {
+	fetcher.merge = merge
+	return fetcher
+}
+
+// WithFullReloadInterval 设置增量模式下全量重新加载的间隔；interval <= 0 表示仅在没有缓存数据时全量加载。
+func (fetcher *CachedDataFetcher[Data]) WithFullReloadInterval(interval time.Duration) *CachedDataFetcher[Data] {
+	fetcher.fullReloadInterval = interval
+	return fetcher
+}
+
 func (fetcher *CachedDataFetcher[Data]) WithOthersSubscribed(timeout time.Duration, others ...interface {
 	RegisterCallbackFuncLite(func(context.Context), time.Duration)
 }) *CachedDataFetcher[Data] {
@@ -240,6 +320,13 @@ func (fetcher *CachedDataFetcher[Data]) GetCached() (Data, time.Time) {
 	return fetcher.getCached()
 }
 
+func (fetcher *CachedDataFetcher[Data]) GetTimedValue() *TimedValue[Data] {
+	fetcher.dataMutex.RLock()
+	defer fetcher.dataMutex.RUnlock()
+
+	return fetcher.data
+}
+
 func (fetcher *CachedDataFetcher[Data]) Get() (Data, time.Time, bool) {
 	return fetcher.GetWithExpires(0)
 }
@@ -289,8 +376,6 @@ func (fetcher *CachedDataFetcher[Data]) FetchWithExpires(ctx context.Context, ex
 }
 
 func (fetcher *CachedDataFetcher[Data]) FetchWithExpiresPro(ctx context.Context, expiresDuration, fallbackDuration time.Duration, logger *zap.Logger) (data Data, ok bool) {
-	data, t, _ := fetcher.FetchWithExpires(ctx, expiresDuration)
-
 	if logger == nil {
 		logger = NopLogger
 	}
@@ -299,6 +384,8 @@ func (fetcher *CachedDataFetcher[Data]) FetchWithExpiresPro(ctx context.Context,
 		fallbackDuration = expiresDuration
 	}
 
+	data, t, _ := fetcher.fetchWithSince(ctx, fetcher.SinceTimeFromExpiresDuration(expiresDuration), fallbackDuration)
+
 	if time.Since(t) > fallbackDuration {
 		logger.Warn("FetchDataExpired",
 			zap.Duration("FallbackDuration", fallbackDuration),
@@ -312,20 +399,22 @@ func (fetcher *CachedDataFetcher[Data]) FetchWithExpiresPro(ctx context.Context,
 }
 
 func (fetcher *CachedDataFetcher[Data]) FetchWithSince(ctx context.Context, since time.Time) (data Data, t time.Time, err error) {
+	return fetcher.fetchWithSince(ctx, since, 0)
+}
+
+// fetchWithSince 开启 stale-while-revalidate 时，拉取时间距今不超过 fallbackDuration 的过期数据直接返回
+func (fetcher *CachedDataFetcher[Data]) fetchWithSince(ctx context.Context, since time.Time, fallbackDuration time.Duration) (data Data, t time.Time, err error) {
 	data, t, ok := fetcher.getWithSince(since)
 	if ok {
 		return
 	}
 
-	fetcher.mutex.Lock()
-	defer fetcher.mutex.Unlock()
-
-	data, t, ok = fetcher.getWithSince(since)
-	if ok {
+	if fetcher.revalidatable(t, fallbackDuration) {
+		fetcher.revalidate(ctx, since)
 		return
 	}
 
-	data, t, err = fetcher.refetch(ctx, since)
+	data, t, err = fetcher.fetchShared(ctx, since, true, nil)
 	if err == nil {
 		return
 	}
@@ -347,24 +436,43 @@ func (fetcher *CachedDataFetcher[Data]) RefreshLowerCache(ctx context.Context) (
 }
 
 func (fetcher *CachedDataFetcher[Data]) RefreshWithSinceTime(ctx context.Context, since time.Time) (data Data, t time.Time, err error) {
-	fetcher.mutex.Lock()
-	defer fetcher.mutex.Unlock()
-
-	return fetcher.refetch(ctx, since)
+	return fetcher.fetchShared(ctx, since, false, nil)
 }
 
 func (fetcher *CachedDataFetcher[Data]) TriggerRefreshLowerCache(ctx context.Context) {
 	fetcher.RefreshLowerCache(ctx)
 }
 
-func (fetcher *CachedDataFetcher[Data]) refetch(ctx context.Context, sinceTime time.Time) (data Data, t time.Time, err error) {
+// ReloadFull 立即全量重新加载；未开启增量模式时与 Refresh 相同。
+func (fetcher *CachedDataFetcher[Data]) ReloadFull(ctx context.Context) (data Data, t time.Time, err error) {
+	fetcher.mutex.Lock()
+	fetcher.fullReloadPending = true
+	fetcher.mutex.Unlock()
+
+	return fetcher.Refresh(ctx)
+}
+
+// refetch 拉取并更新缓存；fetcher 未设置比较函数时使用 fallbackEqual 判断数据是否变化
+func (fetcher *CachedDataFetcher[Data]) refetch(ctx context.Context, sinceTime time.Time, fallbackEqual func(a, b Data) bool) (data Data, t time.Time, err error) {
+	current := fetcher.GetTimedValue()
+
+	merge, incremental := fetcher.prepareMerge(current)
+	if merge != nil {
+		sinceTime = time.Time{}
+		if incremental {
+			sinceTime = current.t
+		}
+	}
+
 	logger := fetcher.With(
 		zap.Time("SinceTime", sinceTime),
-		zap.Time("CurrentDataTime", fetcher.data.Time()),
+		zap.Time("CurrentDataTime", current.Time()),
+		zap.Bool("Incremental", incremental),
 	)
 
 	logger.Info("Refetching")
 
+	fetchingTime := time.Now()
 	data, t, err = fetcher.fetcher(ctx, sinceTime)
 	if err != nil {
 		logger.Error("RefetchFailed",
@@ -381,15 +489,149 @@ func (fetcher *CachedDataFetcher[Data]) refetch(ctx context.Context, sinceTime t
 		t = time.Now()
 	}
 
-	fetcher.data = NewTimedValue(data, t)
+	if incremental {
+		data = merge(current.value, data)
+	} else if merge != nil {
+		fetcher.mutex.Lock()
+		fetcher.fullReloadTime = fetchingTime
+		fetcher.fullReloadPending = false
+		fetcher.mutex.Unlock()
+	}
+
+	fetcher.setCached(NewTimedValue(data, t))
+
+	fetcher.mutex.Lock()
+	callbacks, equal := fetcher.callbacks, fetcher.equal
+	fetcher.mutex.Unlock()
+
+	if equal == nil {
+		equal = fallbackEqual
+	}
+
+	if equal != nil && current != nil && equal(current.value, data) {
+		logger.Debug("RefetchedUnchanged")
+		return
+	}
 
 	// call it asynchronously to avoid dead lock
 	// in case that callbacks may call fetcher method again
-	go fetcher.callbacks.Call(data, t)
+	go callbacks.Call(data, t)
 
 	return
 }
 
+// prepareMerge 返回合并函数，并判断本次拉取是否为增量拉取
+func (fetcher *CachedDataFetcher[Data]) prepareMerge(current *TimedValue[Data]) (merge func(old, delta Data) Data, incremental bool) {
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+
+	merge = fetcher.merge
+	if merge == nil || current == nil || fetcher.fullReloadPending {
+		return
+	}
+
+	if fetcher.fullReloadInterval > 0 && time.Since(fetcher.fullReloadTime) >= fetcher.fullReloadInterval {
+		return
+	}
+
+	return merge, true
+}
+
+// fetchShared 拉取数据，并发调用合并为一次上游请求；recheck 为 true 时先检查缓存是否已被其他调用刷新。
+// recheck 为 false（显式刷新）时不复用调用前已开始或要求更宽松的拉取，等其结束后重新发起。
+// 拉取在后台进行，不受任一调用方 ctx 的影响，各调用方只在自己的 ctx 结束时提前返回。
+func (fetcher *CachedDataFetcher[Data]) fetchShared(ctx context.Context, since time.Time, recheck bool, fallbackEqual func(a, b Data) bool) (data Data, t time.Time, err error) {
+	requested := time.Now()
+
+	for {
+		fetcher.mutex.Lock()
+
+		if recheck {
+			if data, t, ok := fetcher.getWithSince(since); ok {
+				fetcher.mutex.Unlock()
+				return data, t, nil
+			}
+		}
+
+		if current := fetcher.flight; current != nil && !recheck && !current.covers(since, requested) {
+			fetcher.mutex.Unlock()
+
+			if _, _, err = current.wait(ctx); ctx != nil && ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		flight, leader := fetcher.joinFlight(since, fallbackEqual)
+		fetcher.mutex.Unlock()
+
+		if leader {
+			go fetcher.fly(detachContext(ctx), flight, since)
+		}
+
+		return flight.wait(ctx)
+	}
+}
+
+// revalidate 在后台刷新数据，已有拉取进行中时不再发起
+func (fetcher *CachedDataFetcher[Data]) revalidate(ctx context.Context, since time.Time) {
+	fetcher.mutex.Lock()
+	flight, leader := fetcher.joinFlight(since, nil)
+	fetcher.mutex.Unlock()
+
+	if !leader {
+		return
+	}
+
+	go fetcher.fly(detachContext(ctx), flight, since)
+}
+
+func (fetcher *CachedDataFetcher[Data]) revalidatable(t time.Time, fallbackDuration time.Duration) bool {
+	if !fetcher.staleWhileRevalidate || fallbackDuration <= 0 || t.IsZero() {
+		return false
+	}
+
+	return time.Since(t) <= fallbackDuration
+}
+
+// detachContext 返回只继承 ctx 中的值、不会被取消的 ctx，供多个调用方共享的后台拉取使用
+func detachContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return context.WithoutCancel(ctx)
+}
+
+// joinFlight 需持有 mutex 调用；返回进行中的拉取，没有则新建一个并由调用方（leader）负责执行
+func (fetcher *CachedDataFetcher[Data]) joinFlight(since time.Time, fallbackEqual func(a, b Data) bool) (*cachedDataFetcherFlight[Data], bool) {
+	if fetcher.flight != nil {
+		return fetcher.flight, false
+	}
+
+	fetcher.flight = &cachedDataFetcherFlight[Data]{
+		since:   since,
+		started: time.Now(),
+		equal:   fallbackEqual,
+		done:    make(chan struct{}),
+	}
+
+	return fetcher.flight, true
+}
+
+func (fetcher *CachedDataFetcher[Data]) fly(ctx context.Context, flight *cachedDataFetcherFlight[Data], since time.Time) {
+	defer func() {
+		fetcher.mutex.Lock()
+		fetcher.flight = nil
+		fetcher.mutex.Unlock()
+
+		close(flight.done)
+	}()
+
+	defer basic.RecoverPanic(&flight.err)
+
+	flight.data, flight.t, flight.err = fetcher.refetch(ctx, since, flight.equal)
+}
+
 func (fetcher *CachedDataFetcher[Data]) getWithExpires(expiresDuration time.Duration) (Data, time.Time, bool) {
 	return fetcher.getWithSince(fetcher.SinceTimeFromExpiresDuration(expiresDuration))
 }
@@ -401,30 +643,107 @@ func (fetcher *CachedDataFetcher[Data]) getWithSince(since time.Time) (data Data
 }
 
 func (fetcher *CachedDataFetcher[Data]) getCached() (data Data, t time.Time) {
-	return fetcher.data.ValueAndTime()
+	return fetcher.GetTimedValue().ValueAndTime()
+}
+
+func (fetcher *CachedDataFetcher[Data]) setCached(data *TimedValue[Data]) {
+	fetcher.dataMutex.Lock()
+	defer fetcher.dataMutex.Unlock()
+
+	fetcher.data = data
+}
+
+// CachedDataFetcherStore 为 CachedDataFetcherStoreMap 的底层存储，SyncMap 和 Cache 均满足该接口。
+type CachedDataFetcherStore[Key comparable, Data any] interface {
+	LoadOrCreate(ctx context.Context, key Key, create func(ctx context.Context, key Key) (*CachedDataFetcher[Data], error)) (*CachedDataFetcher[Data], bool, error)
 }
 
 type CachedDataFetcherMap[Key comparable, Data any] SyncMap[Key, *CachedDataFetcher[Data]]
 
 func NewCachedDataFetcherMap[Key comparable, Data any](
 	cap int,
-	fetchFunc func (ctx context.Context, key Key, sinceTime time.Time) (Data, time.Time, error),
+	fetchFunc func(ctx context.Context, key Key, sinceTime time.Time) (Data, time.Time, error),
 ) *CachedDataFetcherMap[Key, Data] {
-	createFunc := func (ctx context.Context, key Key) (*CachedDataFetcher[Data], error) {
-		return NewCachedDataFetcher(func (ctx context.Context, sinceTime time.Time) (Data, time.Time, error) {
+	return (*CachedDataFetcherMap[Key, Data])(NewSyncMapPro(cap, newCachedDataFetcherCreateFunc(fetchFunc)))
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) SyncMapping() *SyncMap[Key, *CachedDataFetcher[Data]] {
+	return (*SyncMap[Key, *CachedDataFetcher[Data]])(m)
+}
+
+// StoreMap 返回以底层 SyncMap 为存储的 CachedDataFetcherStoreMap
+func (m *CachedDataFetcherMap[Key, Data]) StoreMap() *CachedDataFetcherStoreMap[Key, Data] {
+	return &CachedDataFetcherStoreMap[Key, Data]{
+		store: m.SyncMapping(),
+	}
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) LoadFetcher(ctx context.Context, key Key) (*CachedDataFetcher[Data], error) {
+	return m.StoreMap().LoadFetcher(ctx, key)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) Fetch(ctx context.Context, key Key) (data Data, t time.Time, err error) {
+	return m.StoreMap().Fetch(ctx, key)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) FetchLite(ctx context.Context, key Key) (data Data, err error) {
+	return m.StoreMap().FetchLite(ctx, key)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) FetchWithExpires(ctx context.Context, key Key, expires time.Duration) (data Data, t time.Time, err error) {
+	return m.StoreMap().FetchWithExpires(ctx, key, expires)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) FetchWithExpiresLite(ctx context.Context, key Key, expires time.Duration) (data Data, err error) {
+	return m.StoreMap().FetchWithExpiresLite(ctx, key, expires)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) FetchWithSince(ctx context.Context, key Key, since time.Time) (data Data, t time.Time, err error) {
+	return m.StoreMap().FetchWithSince(ctx, key, since)
+}
+
+func (m *CachedDataFetcherMap[Key, Data]) FetchWithSinceLite(ctx context.Context, key Key, since time.Time) (data Data, err error) {
+	return m.StoreMap().FetchWithSinceLite(ctx, key, since)
+}
+
+func newCachedDataFetcherCreateFunc[Key comparable, Data any](
+	fetchFunc func(ctx context.Context, key Key, sinceTime time.Time) (Data, time.Time, error),
+) func(ctx context.Context, key Key) (*CachedDataFetcher[Data], error) {
+	return func(ctx context.Context, key Key) (*CachedDataFetcher[Data], error) {
+		return NewCachedDataFetcher(func(ctx context.Context, sinceTime time.Time) (Data, time.Time, error) {
 			return fetchFunc(ctx, key, sinceTime)
 		}), nil
 	}
+}
 
-	return (*CachedDataFetcherMap[Key, Data])(NewSyncMapPro(cap, createFunc))
+// CachedDataFetcherStoreMap 与 CachedDataFetcherMap 用法相同，但底层存储可替换，
+// 例如传入有界的 Cache 以淘汰不活跃的键。
+type CachedDataFetcherStoreMap[Key comparable, Data any] struct {
+	store      CachedDataFetcherStore[Key, Data]
+	createFunc func(ctx context.Context, key Key) (*CachedDataFetcher[Data], error) // 为 nil 时使用存储自身的创建函数
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) SyncMapping() *SyncMap[Key, *CachedDataFetcher[Data]] {
-	return (*SyncMap[Key, *CachedDataFetcher[Data]])(m)
+func NewCachedDataFetcherMapWithStore[Key comparable, Data any](
+	store CachedDataFetcherStore[Key, Data],
+	fetchFunc func(ctx context.Context, key Key, sinceTime time.Time) (Data, time.Time, error),
+) *CachedDataFetcherStoreMap[Key, Data] {
+	return &CachedDataFetcherStoreMap[Key, Data]{
+		store:      store,
+		createFunc: newCachedDataFetcherCreateFunc(fetchFunc),
+	}
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) Fetch(ctx context.Context, key Key) (data Data, t time.Time, err error) {
-	fetcher, _, err := m.SyncMapping().LoadOrCreate(ctx, key, nil)
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetcherStore() CachedDataFetcherStore[Key, Data] {
+	return m.store
+}
+
+func (m *CachedDataFetcherStoreMap[Key, Data]) LoadFetcher(ctx context.Context, key Key) (*CachedDataFetcher[Data], error) {
+	fetcher, _, err := m.store.LoadOrCreate(ctx, key, m.createFunc)
+	return fetcher, err
+}
+
+func (m *CachedDataFetcherStoreMap[Key, Data]) Fetch(ctx context.Context, key Key) (data Data, t time.Time, err error) {
+	fetcher, err := m.LoadFetcher(ctx, key)
 	if err != nil {
 		return
 	}
@@ -432,13 +751,13 @@ func (m *CachedDataFetcherMap[Key, Data]) Fetch(ctx context.Context, key Key) (d
 	return fetcher.Fetch(ctx)
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) FetchLite(ctx context.Context, key Key) (data Data, err error) {
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetchLite(ctx context.Context, key Key) (data Data, err error) {
 	data, _, err = m.Fetch(ctx, key)
 	return
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) FetchWithExpires(ctx context.Context, key Key, expires time.Duration) (data Data, t time.Time, err error) {
-	fetcher, _, err := m.SyncMapping().LoadOrCreate(ctx, key, nil)
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetchWithExpires(ctx context.Context, key Key, expires time.Duration) (data Data, t time.Time, err error) {
+	fetcher, err := m.LoadFetcher(ctx, key)
 	if err != nil {
 		return
 	}
@@ -446,13 +765,13 @@ func (m *CachedDataFetcherMap[Key, Data]) FetchWithExpires(ctx context.Context,
 	return fetcher.FetchWithExpires(ctx, expires)
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) FetchWithExpiresLite(ctx context.Context, key Key, expires time.Duration) (data Data, err error) {
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetchWithExpiresLite(ctx context.Context, key Key, expires time.Duration) (data Data, err error) {
 	data, _, err = m.FetchWithExpires(ctx, key, expires)
 	return
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) FetchWithSince(ctx context.Context, key Key, since time.Time) (data Data, t time.Time, err error) {
-	fetcher, _, err := m.SyncMapping().LoadOrCreate(ctx, key, nil)
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetchWithSince(ctx context.Context, key Key, since time.Time) (data Data, t time.Time, err error) {
+	fetcher, err := m.LoadFetcher(ctx, key)
 	if err != nil {
 		return
 	}
@@ -460,7 +779,7 @@ func (m *CachedDataFetcherMap[Key, Data]) FetchWithSince(ctx context.Context, ke
 	return fetcher.FetchWithSince(ctx, since)
 }
 
-func (m *CachedDataFetcherMap[Key, Data]) FetchWithSinceLite(ctx context.Context, key Key, since time.Time) (data Data, err error) {
+func (m *CachedDataFetcherStoreMap[Key, Data]) FetchWithSinceLite(ctx context.Context, key Key, since time.Time) (data Data, err error) {
 	data, _, err = m.FetchWithSince(ctx, key, since)
 	return
 }
@@ -0,0 +1,185 @@
+package stl
+
+import (
+	"context"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CachedDataFetcherRefresher 按固定间隔在后台主动刷新 CachedDataFetcher，
+// 使请求路径上的 Fetch* 始终命中未过期的缓存。
+type CachedDataFetcherRefresher[Data any] struct {
+	fetcher    *CachedDataFetcher[Data]
+	interval   time.Duration // 刷新间隔，未设置时取 fetcher 的过期时长
+	jitter     time.Duration // 每次等待额外增加 [0, jitter) 的随机时长，避免多实例同时刷新
+	minBackoff time.Duration // 首次失败后的重试等待，之后逐次翻倍
+	maxBackoff time.Duration // 重试等待上限，未设置时取刷新间隔
+	timeout    time.Duration // 单次刷新的等待超时，超时后拉取仍在后台完成并更新缓存
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mutex  sync.Mutex
+}
+
+func (fetcher *CachedDataFetcher[Data]) BuildRefresher(interval time.Duration) *CachedDataFetcherRefresher[Data] {
+	return &CachedDataFetcherRefresher[Data]{
+		fetcher:    fetcher,
+		interval:   interval,
+		minBackoff: time.Second,
+	}
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) WithInterval(interval time.Duration) *CachedDataFetcherRefresher[Data] {
+	refresher.interval = interval
+	return refresher
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) WithJitter(jitter time.Duration) *CachedDataFetcherRefresher[Data] {
+	refresher.jitter = jitter
+	return refresher
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) WithBackoff(minBackoff, maxBackoff time.Duration) *CachedDataFetcherRefresher[Data] {
+	refresher.minBackoff = minBackoff
+	refresher.maxBackoff = maxBackoff
+	return refresher
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) WithTimeout(timeout time.Duration) *CachedDataFetcherRefresher[Data] {
+	refresher.timeout = timeout
+	return refresher
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) Fetcher() *CachedDataFetcher[Data] {
+	return refresher.fetcher
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) Running() bool {
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+
+	return refresher.done != nil
+}
+
+// Start 启动后台刷新，启动时立即刷新一次；重复调用无副作用。
+// 后台刷新得到的数据未变化时不触发回调，fetcher 未设置比较函数时以 reflect.DeepEqual 判断，
+// 这只影响后台刷新，不改变 fetcher 本身的行为。
+func (refresher *CachedDataFetcherRefresher[Data]) Start(ctx context.Context) *CachedDataFetcherRefresher[Data] {
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+
+	if refresher.done != nil {
+		return refresher
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, refresher.cancel = context.WithCancel(ctx)
+	refresher.done = make(chan struct{})
+
+	go refresher.run(ctx, refresher.done)
+
+	return refresher
+}
+
+// Stop 停止后台刷新并等待刷新协程退出；已发起的拉取可能由其他调用方共享，会在后台继续完成。
+func (refresher *CachedDataFetcherRefresher[Data]) Stop() {
+	refresher.mutex.Lock()
+	cancel, done := refresher.cancel, refresher.done
+	refresher.cancel, refresher.done = nil, nil
+	refresher.mutex.Unlock()
+
+	if done == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	failures := 0
+	for {
+		if err := refresher.refresh(ctx); err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(refresher.nextDelay(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) refresh(ctx context.Context) error {
+	if refresher.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, refresher.timeout)
+		defer cancel()
+	}
+
+	_, _, err := refresher.fetcher.fetchShared(ctx, time.Now(), false, deepEqual[Data])
+	if err != nil && ctx.Err() == nil {
+		refresher.fetcher.Warn("AutoRefreshFailed",
+			zap.Error(err),
+		)
+	}
+
+	return err
+}
+
+func deepEqual[Data any](a, b Data) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) getInterval() time.Duration {
+	if refresher.interval > 0 {
+		return refresher.interval
+	}
+
+	if refresher.fetcher.expiresDuration > 0 {
+		return refresher.fetcher.expiresDuration
+	}
+
+	return time.Minute
+}
+
+func (refresher *CachedDataFetcherRefresher[Data]) nextDelay(failures int) (delay time.Duration) {
+	interval := refresher.getInterval()
+
+	delay = interval
+	if failures > 0 && refresher.minBackoff > 0 {
+		maxBackoff := refresher.maxBackoff
+		if maxBackoff <= 0 {
+			maxBackoff = interval
+		}
+
+		delay = refresher.minBackoff
+		for i := 1; i < failures && delay < maxBackoff; i++ {
+			delay *= 2
+		}
+
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+
+	if refresher.jitter > 0 {
+		delay += rand.N(refresher.jitter)
+	}
+
+	return
+}
@@ -0,0 +1,75 @@
+package stl
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedDataFetcherRefresher(t *testing.T) {
+	var calls atomic.Int32
+	var callbacks atomic.Int32
+
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int32, time.Time, error) {
+		// 每两次拉取数据才变化一次
+		return (calls.Add(1) + 1) / 2, time.Now(), nil
+	}).WithExpiresDuration(time.Hour)
+
+	fetcher.NewCallbackLite(func(context.Context) {
+		callbacks.Add(1)
+	}, 0)
+
+	refresher := fetcher.BuildRefresher(time.Millisecond * 50).
+		WithJitter(time.Millisecond * 10).
+		Start(context.Background())
+	assert.True(t, refresher.Running())
+
+	time.Sleep(time.Millisecond * 280)
+	refresher.Stop()
+	assert.False(t, refresher.Running())
+
+	// 停止前发起的拉取在后台完成
+	time.Sleep(time.Millisecond * 50)
+	n := calls.Load()
+	assert.GreaterOrEqual(t, n, int32(4))
+
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, n, calls.Load())
+	assert.Equal(t, (n+1)/2, callbacks.Load())
+
+	data, _, ok := fetcher.Get()
+	assert.True(t, ok)
+	assert.Equal(t, (n+1)/2, data)
+
+	// 后台刷新的比较只对其自身生效，显式刷新得到相同数据时照常触发回调
+	fetcher.WithFetcher(func(ctx context.Context, since time.Time) (int32, time.Time, error) {
+		return data, time.Now(), nil
+	})
+	_, _, err := fetcher.Refresh(nil)
+	assert.Nil(t, err)
+
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, (n+1)/2+1, callbacks.Load())
+}
+
+func TestCachedDataFetcherRefresherBackoff(t *testing.T) {
+	refresher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int, time.Time, error) {
+		return 0, time.Time{}, errors.New("upstream down")
+	}).BuildRefresher(time.Minute).WithBackoff(time.Second, time.Second*5)
+
+	assert.Equal(t, time.Minute, refresher.nextDelay(0))
+	assert.Equal(t, time.Second, refresher.nextDelay(1))
+	assert.Equal(t, time.Second*2, refresher.nextDelay(2))
+	assert.Equal(t, time.Second*4, refresher.nextDelay(3))
+	assert.Equal(t, time.Second*5, refresher.nextDelay(4))
+	assert.Equal(t, time.Second*5, refresher.nextDelay(100))
+
+	refresher.Start(nil)
+	time.Sleep(time.Millisecond * 10)
+	refresher.Stop()
+	refresher.Stop()
+}
@@ -9,7 +9,10 @@ package stl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -179,3 +182,238 @@ func TestCustomExpireDuration(t *testing.T) {
 	assert.Equal(t, ok, true)
 	assert.NotEqual(t, data3, data2)
 }
+
+func TestCachedDataFetcherSingleflight(t *testing.T) {
+	var calls atomic.Int32
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int32, time.Time, error) {
+		time.Sleep(time.Millisecond * 50)
+		return calls.Add(1), time.Now(), nil
+	}).WithExpiresDuration(time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, _, err := fetcher.Fetch(context.Background())
+			assert.Nil(t, err)
+			assert.Equal(t, int32(1), data)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetcher.Refresh(context.Background())
+		}()
+	}
+	wg.Wait()
+	assert.Less(t, calls.Load(), int32(20))
+}
+
+func TestCachedDataFetcherSingleflightError(t *testing.T) {
+	var calls atomic.Int32
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int, time.Time, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 50)
+		return 0, time.Time{}, errors.New("upstream down")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := fetcher.Fetch(context.Background())
+			assert.NotNil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCachedDataFetcherMapSingleflight(t *testing.T) {
+	var calls atomic.Int32
+	m := NewCachedDataFetcherMap(0, func(ctx context.Context, key string, since time.Time) (string, time.Time, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 50)
+		return key, time.Now(), nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprint(i % 2)
+			data, err := m.FetchLite(context.Background(), key)
+			assert.Nil(t, err)
+			assert.Equal(t, key, data)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCachedDataFetcherStaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int32, time.Time, error) {
+		time.Sleep(time.Millisecond * 50)
+		return calls.Add(1), time.Now(), nil
+	}).
+		WithExpiresDuration(time.Millisecond * 100).
+		WithStaleWhileRevalidate(true)
+
+	fetch := func() (int32, bool) {
+		return fetcher.FetchWithExpiresPro(nil, 0, time.Millisecond*300, nil)
+	}
+
+	data, ok := fetch()
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), data)
+
+	time.Sleep(time.Millisecond * 150)
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		data, ok := fetch()
+		assert.True(t, ok)
+		assert.Equal(t, int32(1), data)
+	}
+	assert.Less(t, time.Since(start), time.Millisecond*50)
+
+	time.Sleep(time.Millisecond * 100)
+	data, _ = fetch()
+	assert.Equal(t, int32(2), data)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// 超过 fallbackDuration 后同步拉取
+	time.Sleep(time.Millisecond * 400)
+	data, ok = fetch()
+	assert.True(t, ok)
+	assert.Equal(t, int32(3), data)
+
+	// 未给出 fallbackDuration 的 Fetch 同步拉取
+	time.Sleep(time.Millisecond * 150)
+	data, _, _ = fetcher.Fetch(nil)
+	assert.Equal(t, int32(4), data)
+}
+
+func TestCachedDataFetcherSharedFlightContext(t *testing.T) {
+	release := make(chan struct{})
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int, time.Time, error) {
+		select {
+		case <-release:
+			return 1, time.Now(), nil
+		case <-ctx.Done():
+			return 0, time.Time{}, ctx.Err()
+		}
+	})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, _, err := fetcher.Fetch(leaderCtx)
+		leaderDone <- err
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	waiterDone := make(chan int)
+	go func() {
+		data, _, err := fetcher.Fetch(context.Background())
+		assert.Nil(t, err)
+		waiterDone <- data
+	}()
+
+	// leader 取消只影响其自身
+	time.Sleep(time.Millisecond * 20)
+	cancel()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	close(release)
+	assert.Equal(t, 1, <-waiterDone)
+}
+
+func TestCachedDataFetcherRefreshNotReused(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (int32, time.Time, error) {
+		n := calls.Add(1)
+		started <- struct{}{}
+		if n == 1 {
+			<-release
+		}
+		return n, time.Now(), nil
+	})
+
+	go fetcher.Fetch(nil)
+	<-started
+
+	// 刷新不能复用调用前已开始的拉取
+	refreshed := make(chan int32)
+	go func() {
+		data, _, err := fetcher.Refresh(nil)
+		assert.Nil(t, err)
+		refreshed <- data
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+	assert.Equal(t, int32(2), <-refreshed)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCachedDataFetcherMerge(t *testing.T) {
+	var sinces []time.Time
+	version := 0
+
+	fetcher := NewCachedDataFetcher(func(ctx context.Context, since time.Time) (map[string]int, time.Time, error) {
+		sinces = append(sinces, since)
+		version++
+		if since.IsZero() {
+			return map[string]int{"full": version}, time.Now(), nil
+		}
+		return map[string]int{fmt.Sprint("delta", version): version}, time.Now(), nil
+	}).
+		WithMergeFunc(ConcatMap[string, int, map[string]int]).
+		WithFullReloadInterval(time.Millisecond * 200)
+
+	var mutex sync.Mutex
+	var merged []map[string]int
+	fetcher.NewCallback(func(ctx context.Context, data map[string]int, t time.Time) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		merged = append(merged, data)
+	}, 0)
+
+	data, t1, err := fetcher.Refresh(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"full": 1}, data)
+	assert.True(t, sinces[0].IsZero())
+
+	data, _, err = fetcher.Refresh(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"full": 1, "delta2": 2}, data)
+	assert.Equal(t, t1, sinces[1])
+
+	time.Sleep(time.Millisecond * 50)
+	mutex.Lock()
+	assert.Contains(t, merged, data)
+	mutex.Unlock()
+
+	data, _, err = fetcher.ReloadFull(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"full": 3}, data)
+
+	data, _, _ = fetcher.Refresh(nil)
+	assert.Equal(t, map[string]int{"full": 3, "delta4": 4}, data)
+
+	time.Sleep(time.Millisecond * 250)
+	data, _, _ = fetcher.Refresh(nil)
+	assert.Equal(t, map[string]int{"full": 5}, data)
+	assert.True(t, sinces[4].IsZero())
+}
@@ -0,0 +1,456 @@
+package stl
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func chanContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// sendChan 向 c 发送 data，ctx 结束时放弃并返回 false
+func sendChan[Data any](ctx context.Context, c chan<- Data, data Data) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case c <- data:
+		return true
+	}
+}
+
+// MergeChans 将多个通道汇入一个通道（fan-in），全部输入关闭或 ctx 结束后关闭输出
+func MergeChans[Data any](ctx context.Context, chans ...<-chan Data) <-chan Data {
+	ctx = chanContext(ctx)
+	out := make(chan Data)
+
+	var wg sync.WaitGroup
+	for _, c := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case data, ok := <-c:
+					if !ok || !sendChan(ctx, out, data) {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// TeeChan 将输入复制到 n 个输出通道，每个值须被全部输出接收后才读取下一个值；
+// 输入关闭或 ctx 结束后关闭全部输出。n <= 0 时不返回输出通道，输入照常读取并丢弃
+func TeeChan[Data any](ctx context.Context, in <-chan Data, n int) []<-chan Data {
+	ctx = chanContext(ctx)
+	n = max(n, 0)
+
+	outs := make([]chan Data, n)
+	for i := range outs {
+		outs[i] = make(chan Data)
+	}
+
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for {
+			var data Data
+			var ok bool
+
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+
+			// 哪个输出先就绪就先发给哪个，发送完的不再参与 select
+			cases := make([]reflect.SelectCase, 0, n+1)
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+			for _, out := range outs {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(out), Send: reflect.ValueOf(&data).Elem()})
+			}
+
+			for len(cases) > 1 {
+				chosen, _, _ := reflect.Select(cases)
+				if chosen == 0 {
+					return
+				}
+				cases = append(cases[:chosen], cases[chosen+1:]...)
+			}
+		}
+	}()
+
+	return Map(outs, func(out chan Data) <-chan Data { return out })
+}
+
+// BroadcastPolicy 为订阅者缓冲区已满时的处理方式
+type BroadcastPolicy int
+
+const (
+	// BroadcastBlock 等待订阅者接收，慢订阅者会拖慢所有订阅者
+	BroadcastBlock BroadcastPolicy = iota
+	// BroadcastDropOldest 丢弃缓冲区中最旧的值
+	BroadcastDropOldest
+	// BroadcastDropNewest 丢弃当前值
+	BroadcastDropNewest
+)
+
+// BroadcastSubscription 为 Broadcaster 的一个订阅
+type BroadcastSubscription[Data any] struct {
+	c       chan Data
+	policy  BroadcastPolicy
+	done    chan struct{}
+	once    sync.Once
+	mutex   sync.Mutex
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// C 返回接收通道，取消订阅或广播结束后关闭
+func (sub *BroadcastSubscription[Data]) C() <-chan Data {
+	return sub.c
+}
+
+// Dropped 返回因缓冲区已满而丢弃的值的个数
+func (sub *BroadcastSubscription[Data]) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
+func (sub *BroadcastSubscription[Data]) close() {
+	sub.once.Do(func() {
+		close(sub.done)
+	})
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if !sub.closed {
+		sub.closed = true
+		close(sub.c)
+	}
+}
+
+func (sub *BroadcastSubscription[Data]) deliver(ctx context.Context, data Data) {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.closed {
+		return
+	}
+
+	switch sub.policy {
+	case BroadcastDropNewest:
+		select {
+		case sub.c <- data:
+		default:
+			sub.dropped.Add(1)
+		}
+	case BroadcastDropOldest:
+		for {
+			select {
+			case sub.c <- data:
+				return
+			default:
+			}
+
+			select {
+			case <-sub.c:
+				sub.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case <-ctx.Done():
+		case <-sub.done:
+		case sub.c <- data:
+		}
+	}
+}
+
+// Broadcaster 将源通道中的每个值分发给全部订阅者（fan-out）。
+// 源通道关闭或 ctx 结束后关闭全部订阅者的通道。
+type Broadcaster[Data any] struct {
+	mutex       sync.Mutex
+	subscribers []*BroadcastSubscription[Data]
+	finished    bool
+	done        chan struct{}
+}
+
+func NewBroadcaster[Data any](ctx context.Context, source <-chan Data) *Broadcaster[Data] {
+	ctx = chanContext(ctx)
+
+	broadcaster := &Broadcaster[Data]{
+		done: make(chan struct{}),
+	}
+
+	go broadcaster.run(ctx, source)
+
+	return broadcaster
+}
+
+func (broadcaster *Broadcaster[Data]) run(ctx context.Context, source <-chan Data) {
+	defer broadcaster.finish()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data, ok := <-source:
+			if !ok {
+				return
+			}
+
+			broadcaster.mutex.Lock()
+			subscribers := DupSlice(broadcaster.subscribers)
+			broadcaster.mutex.Unlock()
+
+			for _, sub := range subscribers {
+				sub.deliver(ctx, data)
+			}
+		}
+	}
+}
+
+func (broadcaster *Broadcaster[Data]) finish() {
+	broadcaster.mutex.Lock()
+	subscribers := broadcaster.subscribers
+	broadcaster.subscribers = nil
+	broadcaster.finished = true
+	broadcaster.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		sub.close()
+	}
+
+	close(broadcaster.done)
+}
+
+// Subscribe 添加订阅者，buffer 为其缓冲区大小；丢弃策略下 buffer 至少为 1。
+// 广播已结束时返回的订阅通道已关闭。
+func (broadcaster *Broadcaster[Data]) Subscribe(buffer int, policy BroadcastPolicy) *BroadcastSubscription[Data] {
+	if policy != BroadcastBlock {
+		buffer = max(buffer, 1)
+	}
+
+	sub := &BroadcastSubscription[Data]{
+		c:      make(chan Data, max(buffer, 0)),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	if broadcaster.finished {
+		sub.close()
+	} else {
+		broadcaster.subscribers = append(broadcaster.subscribers, sub)
+	}
+
+	return sub
+}
+
+// Unsubscribe 取消订阅并关闭其通道
+func (broadcaster *Broadcaster[Data]) Unsubscribe(sub *BroadcastSubscription[Data]) {
+	broadcaster.mutex.Lock()
+	broadcaster.subscribers = Filter(broadcaster.subscribers, func(other *BroadcastSubscription[Data]) bool {
+		return other != sub
+	})
+	broadcaster.mutex.Unlock()
+
+	sub.close()
+}
+
+// Done 在广播结束、全部订阅者的通道关闭后关闭
+func (broadcaster *Broadcaster[Data]) Done() <-chan struct{} {
+	return broadcaster.done
+}
+
+// BatchChan 将输入按批输出：攒够 size 个，或距本批第一个值已过 interval 时输出一批。
+// 输入关闭后输出剩余的值再关闭输出；ctx 结束时丢弃未输出的值并关闭输出。
+func BatchChan[Data any](ctx context.Context, in <-chan Data, size int, interval time.Duration) <-chan []Data {
+	ctx = chanContext(ctx)
+	size = max(size, 1)
+	out := make(chan []Data)
+
+	go func() {
+		defer close(out)
+
+		var batch []Data
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+
+			if len(batch) == 0 {
+				return true
+			}
+
+			ok := sendChan(ctx, out, batch)
+			batch = nil
+			return ok
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case data, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, data)
+				if len(batch) == 1 && interval > 0 {
+					timer = time.NewTimer(interval)
+					timeout = timer.C
+				}
+
+				if len(batch) >= size && !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// DebounceChan 在输入静默 wait 之后输出最后一个值；输入关闭后输出尚未输出的值再关闭输出
+func DebounceChan[Data any](ctx context.Context, in <-chan Data, wait time.Duration) <-chan Data {
+	ctx = chanContext(ctx)
+	out := make(chan Data)
+
+	go func() {
+		defer close(out)
+
+		var latest Data
+		var pending bool
+
+		timer := time.NewTimer(wait)
+		timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				pending = false
+				if !sendChan(ctx, out, latest) {
+					return
+				}
+			case data, ok := <-in:
+				if !ok {
+					timer.Stop()
+					if pending {
+						sendChan(ctx, out, latest)
+					}
+					return
+				}
+
+				latest, pending = data, true
+				timer.Reset(wait)
+			}
+		}
+	}()
+
+	return out
+}
+
+// ThrottleChan 限制输出频率：每个 interval 内至多输出一个值。
+// 空闲时到来的值立即输出；间隔内到来的值只保留最新的一个，在间隔结束时输出。
+// 输入关闭后输出尚未输出的值再关闭输出
+func ThrottleChan[Data any](ctx context.Context, in <-chan Data, interval time.Duration) <-chan Data {
+	ctx = chanContext(ctx)
+	out := make(chan Data)
+
+	go func() {
+		defer close(out)
+
+		var latest Data
+		var pending bool
+
+		var timer *time.Timer
+		var window <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		emit := func(data Data) bool {
+			if !sendChan(ctx, out, data) {
+				return false
+			}
+
+			timer = time.NewTimer(interval)
+			window = timer.C
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-window:
+				window = nil
+				if pending {
+					pending = false
+					if !emit(latest) {
+						return
+					}
+				}
+			case data, ok := <-in:
+				if !ok {
+					if pending {
+						sendChan(ctx, out, latest)
+					}
+					return
+				}
+
+				if window != nil {
+					latest, pending = data, true
+				} else if !emit(data) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
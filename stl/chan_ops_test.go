@@ -0,0 +1,199 @@
+package stl
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readChan[Data any](c <-chan Data) []Data {
+	var datas []Data
+	for data := range c {
+		datas = append(datas, data)
+	}
+	return datas
+}
+
+func TestMergeChans(t *testing.T) {
+	a := NewChanFromDatasX[chan int](1, 2, 3)
+	b := NewChanFromDatasX[chan int](4, 5)
+	close(a)
+	close(b)
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, readChan(MergeChans(context.Background(), a, b)))
+	assert.Empty(t, readChan(MergeChans[int](nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	merged := MergeChans(ctx, never)
+	cancel()
+
+	_, ok := <-merged
+	assert.False(t, ok)
+}
+
+func TestTeeChan(t *testing.T) {
+	in := NewChanFromDatasX[chan error](nil, context.Canceled)
+	close(in)
+
+	outs := TeeChan(nil, in, 3)
+
+	var wg sync.WaitGroup
+	results := make([][]error, len(outs))
+	for i, out := range outs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i == 0 {
+				time.Sleep(time.Millisecond * 10)
+			}
+			results[i] = readChan(out)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		assert.Equal(t, []error{nil, context.Canceled}, result)
+	}
+}
+
+func TestTeeChanNonPositive(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		in := make(chan int)
+		assert.Empty(t, TeeChan(nil, in, n))
+
+		// 没有输出时输入照常被读取
+		in <- 1
+		close(in)
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	source := make(chan int)
+	broadcaster := NewBroadcaster(context.Background(), source)
+
+	blocking := broadcaster.Subscribe(0, BroadcastBlock)
+	oldest := broadcaster.Subscribe(2, BroadcastDropOldest)
+	newest := broadcaster.Subscribe(2, BroadcastDropNewest)
+	leaving := broadcaster.Subscribe(0, BroadcastBlock)
+	broadcaster.Unsubscribe(leaving)
+
+	_, ok := <-leaving.C()
+	assert.False(t, ok)
+
+	var received []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		received = readChan(blocking.C())
+	}()
+
+	for i := 1; i <= 5; i++ {
+		source <- i
+	}
+	close(source)
+
+	<-broadcaster.Done()
+	<-done
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, received)
+	assert.Equal(t, []int{4, 5}, readChan(oldest.C()))
+	assert.Equal(t, uint64(3), oldest.Dropped())
+	assert.Equal(t, []int{1, 2}, readChan(newest.C()))
+	assert.Equal(t, uint64(3), newest.Dropped())
+
+	late := broadcaster.Subscribe(1, BroadcastBlock)
+	_, ok = <-late.C()
+	assert.False(t, ok)
+}
+
+func TestBroadcasterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := make(chan int, 1)
+	broadcaster := NewBroadcaster(ctx, source)
+	stuck := broadcaster.Subscribe(0, BroadcastBlock)
+
+	source <- 1
+	time.Sleep(time.Millisecond * 10)
+	cancel()
+
+	select {
+	case <-broadcaster.Done():
+	case <-time.After(time.Second):
+		t.Fatal("broadcaster not finished after cancel")
+	}
+
+	_, ok := <-stuck.C()
+	assert.False(t, ok)
+}
+
+func TestBatchChan(t *testing.T) {
+	in := make(chan int)
+	out := BatchChan(context.Background(), in, 3, time.Millisecond*30)
+
+	go func() {
+		for i := 1; i <= 4; i++ {
+			in <- i
+		}
+		time.Sleep(time.Millisecond * 60)
+		in <- 5
+		close(in)
+	}()
+
+	assert.Equal(t, [][]int{{1, 2, 3}, {4}, {5}}, readChan(out))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pending := make(chan int, 1)
+	pending <- 1
+	out = BatchChan(ctx, pending, 10, 0)
+	time.Sleep(time.Millisecond * 10)
+	cancel()
+	assert.Empty(t, readChan(out))
+}
+
+func TestDebounceChan(t *testing.T) {
+	in := make(chan int)
+	out := DebounceChan(context.Background(), in, time.Millisecond*30)
+
+	go func() {
+		for i := 1; i <= 3; i++ {
+			in <- i
+			time.Sleep(time.Millisecond * 5)
+		}
+		time.Sleep(time.Millisecond * 80)
+		in <- 4
+		in <- 5
+		close(in)
+	}()
+
+	assert.Equal(t, []int{3, 5}, readChan(out))
+}
+
+func TestThrottleChan(t *testing.T) {
+	in := make(chan int)
+	out := ThrottleChan(context.Background(), in, time.Millisecond*50)
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			in <- i
+			time.Sleep(time.Millisecond * 5)
+		}
+		time.Sleep(time.Millisecond * 150)
+		in <- 6
+		close(in)
+	}()
+
+	start := time.Now()
+	var results []int
+	var elapsed []time.Duration
+	for data := range out {
+		results = append(results, data)
+		elapsed = append(elapsed, time.Since(start))
+	}
+
+	assert.Equal(t, []int{1, 5, 6}, results)
+	assert.GreaterOrEqual(t, elapsed[1]-elapsed[0], time.Millisecond*40)
+}
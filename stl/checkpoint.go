@@ -0,0 +1,88 @@
+package stl
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CheckpointStore 持久化读取进度，供 ResumeReader 在进程重启后续读
+type CheckpointStore interface {
+	// LoadCheckpoint 读取进度，没有进度时 ok 为 false
+	LoadCheckpoint() (offset int, ok bool, err error)
+	SaveCheckpoint(offset int) error
+}
+
+// MemoryCheckpointStore 将进度保存在内存中，可在同一进程内多次续读
+type MemoryCheckpointStore struct {
+	mutex  sync.Mutex
+	offset int
+	ok     bool
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+func (store *MemoryCheckpointStore) LoadCheckpoint() (int, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.offset, store.ok, nil
+}
+
+func (store *MemoryCheckpointStore) SaveCheckpoint(offset int) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.offset, store.ok = offset, true
+	return nil
+}
+
+// FileCheckpointStore 将进度以十进制文本保存在文件中，先写临时文件再重命名，避免写一半时崩溃
+type FileCheckpointStore struct {
+	path string
+}
+
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{
+		path: path,
+	}
+}
+
+func (store *FileCheckpointStore) Path() string {
+	return store.path
+}
+
+func (store *FileCheckpointStore) LoadCheckpoint() (int, bool, error) {
+	content, err := os.ReadFile(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	offset, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return offset, true, nil
+}
+
+func (store *FileCheckpointStore) SaveCheckpoint(offset int) error {
+	tmp := store.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(offset)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.path)
+}
+
+// Remove 删除进度文件，文件不存在时不报错
+func (store *FileCheckpointStore) Remove() error {
+	err := os.Remove(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package stl
 
 import (
 	"container/heap"
+	"iter"
 
 	"golang.org/x/exp/constraints"
 )
@@ -189,3 +190,327 @@ func (h *Heap[T]) Pop() T {
 func (h *Heap[T]) Clear() {
 	h.datas = h.datas[:0]
 }
+
+// IndexedHeapHandle 指向 IndexedHeap 中的一个元素，用于更新或删除该元素
+type IndexedHeapHandle[T any] struct {
+	data  T
+	index int
+	owner *IndexedHeap[T]
+}
+
+func (handle *IndexedHeapHandle[T]) Data() T {
+	return handle.data
+}
+
+// Valid 判断元素是否仍在堆中
+func (handle *IndexedHeapHandle[T]) Valid() bool {
+	return handle.owner != nil
+}
+
+type indexedHeapIface[T any] struct {
+	handles []*IndexedHeapHandle[T]
+	less    func(a, b T) bool
+}
+
+func (h *indexedHeapIface[T]) Len() int {
+	return len(h.handles)
+}
+
+func (h *indexedHeapIface[T]) Less(i, j int) bool {
+	return h.less(h.handles[i].data, h.handles[j].data)
+}
+
+func (h *indexedHeapIface[T]) Swap(i, j int) {
+	h.handles[i], h.handles[j] = h.handles[j], h.handles[i]
+	h.handles[i].index = i
+	h.handles[j].index = j
+}
+
+func (h *indexedHeapIface[T]) Push(x any) {
+	handle := x.(*IndexedHeapHandle[T])
+	handle.index = len(h.handles)
+	h.handles = append(h.handles, handle)
+}
+
+func (h *indexedHeapIface[T]) Pop() any {
+	n := len(h.handles)
+	handle := h.handles[n-1]
+	h.handles[n-1] = nil
+	h.handles = h.handles[:n-1]
+	return handle
+}
+
+// IndexedHeap 是可按句柄更新优先级、删除任意元素的堆
+type IndexedHeap[T any] struct {
+	iface indexedHeapIface[T]
+}
+
+func NewIndexedHeap[T any](less func(a, b T) bool, capacity int) *IndexedHeap[T] {
+	return &IndexedHeap[T]{
+		iface: indexedHeapIface[T]{
+			handles: make([]*IndexedHeapHandle[T], 0, capacity),
+			less:    less,
+		},
+	}
+}
+
+func NewIndexedHeapAsContainer[T any](less func(a, b T) bool, capacity int) Container[T] {
+	return NewIndexedHeap(less, capacity)
+}
+
+func (h *IndexedHeap[T]) Len() int {
+	return h.iface.Len()
+}
+
+func (h *IndexedHeap[T]) IsEmpty() bool {
+	return h.iface.Len() == 0
+}
+
+func (h *IndexedHeap[T]) Push(data T) {
+	h.PushHandle(data)
+}
+
+// PushHandle 压入元素并返回其句柄
+func (h *IndexedHeap[T]) PushHandle(data T) *IndexedHeapHandle[T] {
+	handle := &IndexedHeapHandle[T]{
+		data:  data,
+		owner: h,
+	}
+	heap.Push(&h.iface, handle)
+	return handle
+}
+
+func (h *IndexedHeap[T]) TryPeekHandle() (handle *IndexedHeapHandle[T], ok bool) {
+	if h.IsEmpty() {
+		return
+	}
+	return h.iface.handles[0], true
+}
+
+func (h *IndexedHeap[T]) TryPeek() (data T, ok bool) {
+	handle, ok := h.TryPeekHandle()
+	if !ok {
+		return
+	}
+	return handle.data, true
+}
+
+func (h *IndexedHeap[T]) Peek() T {
+	data, _ := h.TryPeek()
+	return data
+}
+
+func (h *IndexedHeap[T]) TryPop() (data T, ok bool) {
+	if h.IsEmpty() {
+		return
+	}
+
+	handle := heap.Pop(&h.iface).(*IndexedHeapHandle[T])
+	handle.owner = nil
+
+	return handle.data, true
+}
+
+func (h *IndexedHeap[T]) Pop() T {
+	data, _ := h.TryPop()
+	return data
+}
+
+// Update 替换句柄对应的元素并调整位置，句柄不属于本堆或已出堆时返回 false
+func (h *IndexedHeap[T]) Update(handle *IndexedHeapHandle[T], data T) bool {
+	if handle.owner != h {
+		return false
+	}
+
+	handle.data = data
+	heap.Fix(&h.iface, handle.index)
+
+	return true
+}
+
+// Fix 在元素的优先级被外部修改后（如 T 为指针）调整其位置
+func (h *IndexedHeap[T]) Fix(handle *IndexedHeapHandle[T]) bool {
+	if handle.owner != h {
+		return false
+	}
+
+	heap.Fix(&h.iface, handle.index)
+	return true
+}
+
+// Remove 删除句柄对应的元素，句柄不属于本堆或已出堆时返回 false
+func (h *IndexedHeap[T]) Remove(handle *IndexedHeapHandle[T]) bool {
+	if handle.owner != h {
+		return false
+	}
+
+	heap.Remove(&h.iface, handle.index)
+	handle.owner = nil
+
+	return true
+}
+
+func (h *IndexedHeap[T]) Clear() {
+	for i, handle := range h.iface.handles {
+		handle.owner = nil
+		h.iface.handles[i] = nil
+	}
+	h.iface.handles = h.iface.handles[:0]
+}
+
+// Deque 是基于环形缓冲区的双端队列。作为 Container 使用时为先进先出队列：
+// Push 从尾部压入，Pop、Peek 作用于头部。
+type Deque[T any] struct {
+	datas []T
+	head  int
+	size  int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{
+		datas: make([]T, capacity),
+	}
+}
+
+func NewDequeAsContainer[T any](capacity int) Container[T] {
+	return NewDeque[T](capacity)
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.datas)
+}
+
+func (d *Deque[T]) grow() {
+	if d.size < len(d.datas) {
+		return
+	}
+
+	datas := make([]T, max(len(d.datas)*2, 8))
+	n := copy(datas, d.datas[d.head:])
+	copy(datas[n:], d.datas[:d.head])
+
+	d.datas = datas
+	d.head = 0
+}
+
+func (d *Deque[T]) PushBack(data T) {
+	d.grow()
+	d.datas[d.index(d.size)] = data
+	d.size++
+}
+
+func (d *Deque[T]) PushFront(data T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.datas)) % len(d.datas)
+	d.datas[d.head] = data
+	d.size++
+}
+
+func (d *Deque[T]) TryPopFront() (data T, ok bool) {
+	if d.size == 0 {
+		return
+	}
+
+	var zero T
+	data, d.datas[d.head] = d.datas[d.head], zero
+	d.head = d.index(1)
+	d.size--
+
+	return data, true
+}
+
+func (d *Deque[T]) TryPopBack() (data T, ok bool) {
+	if d.size == 0 {
+		return
+	}
+
+	var zero T
+	i := d.index(d.size - 1)
+	data, d.datas[i] = d.datas[i], zero
+	d.size--
+
+	return data, true
+}
+
+func (d *Deque[T]) PopFront() T {
+	data, _ := d.TryPopFront()
+	return data
+}
+
+func (d *Deque[T]) PopBack() T {
+	data, _ := d.TryPopBack()
+	return data
+}
+
+func (d *Deque[T]) TryPeekFront() (data T, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	return d.datas[d.head], true
+}
+
+func (d *Deque[T]) TryPeekBack() (data T, ok bool) {
+	if d.size == 0 {
+		return
+	}
+	return d.datas[d.index(d.size-1)], true
+}
+
+func (d *Deque[T]) PeekFront() T {
+	data, _ := d.TryPeekFront()
+	return data
+}
+
+func (d *Deque[T]) PeekBack() T {
+	data, _ := d.TryPeekBack()
+	return data
+}
+
+// At 返回从头部数起第 i 个元素
+func (d *Deque[T]) At(i int) (data T, ok bool) {
+	if i < 0 || i >= d.size {
+		return
+	}
+	return d.datas[d.index(i)], true
+}
+
+func (d *Deque[T]) Push(data T) {
+	d.PushBack(data)
+}
+
+func (d *Deque[T]) Pop() T {
+	return d.PopFront()
+}
+
+func (d *Deque[T]) Peek() T {
+	return d.PeekFront()
+}
+
+func (d *Deque[T]) Clear() {
+	clear(d.datas)
+	d.head = 0
+	d.size = 0
+}
+
+// Seq 从头到尾遍历
+func (d *Deque[T]) Seq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.datas[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (d *Deque[T]) Slice() []T {
+	return ReadSeq[[]T](d.Seq())
+}
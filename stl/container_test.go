@@ -145,3 +145,124 @@ func TestHeapAsContainerFactories(t *testing.T) {
 	assert.Equal(t, 1, custom.Pop())
 	assert.Equal(t, 3, custom.Pop())
 }
+
+func TestIndexedHeap(t *testing.T) {
+	h := NewIndexedHeap(Less[int], 0)
+
+	handles := Map([]int{50, 10, 40, 20, 30}, h.PushHandle)
+	assert.Equal(t, 5, h.Len())
+	assert.Equal(t, 10, h.Peek())
+
+	assert.True(t, h.Update(handles[0], 5))
+	assert.Equal(t, 5, h.Peek())
+
+	assert.True(t, h.Update(handles[1], 45))
+	assert.True(t, h.Remove(handles[3]))
+	assert.False(t, handles[3].Valid())
+	assert.False(t, h.Remove(handles[3]))
+	assert.False(t, h.Update(handles[3], 1))
+
+	other := NewIndexedHeap(Less[int], 0)
+	assert.False(t, other.Remove(handles[2]))
+
+	got := make([]int, 0, 4)
+	for !h.IsEmpty() {
+		got = append(got, h.Pop())
+	}
+	assert.Equal(t, []int{5, 30, 40, 45}, got)
+	assert.False(t, handles[0].Valid())
+	assert.Equal(t, 5, handles[0].Data())
+
+	_, ok := h.TryPop()
+	assert.False(t, ok)
+}
+
+func TestIndexedHeapFix(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+
+	h := NewIndexedHeap(func(a, b *task) bool { return a.priority > b.priority }, 0)
+	low := &task{"low", 1}
+	high := &task{"high", 9}
+	lowHandle := h.PushHandle(low)
+	h.Push(high)
+
+	low.priority = 10
+	assert.True(t, h.Fix(lowHandle))
+
+	handle, ok := h.TryPeekHandle()
+	assert.True(t, ok)
+	assert.Same(t, lowHandle, handle)
+	assert.Equal(t, "low", h.Pop().name)
+
+	h.Clear()
+	assert.True(t, h.IsEmpty())
+	assert.False(t, h.Fix(lowHandle))
+}
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int](0)
+	assert.True(t, d.IsEmpty())
+
+	_, ok := d.TryPopFront()
+	assert.False(t, ok)
+	_, ok = d.TryPeekBack()
+	assert.False(t, ok)
+
+	for i := 1; i <= 10; i++ {
+		d.PushBack(i)
+		d.PushFront(-i)
+	}
+	assert.Equal(t, 20, d.Len())
+	assert.Equal(t, -10, d.PeekFront())
+	assert.Equal(t, 10, d.PeekBack())
+
+	data, ok := d.At(10)
+	assert.True(t, ok)
+	assert.Equal(t, 1, data)
+	_, ok = d.At(20)
+	assert.False(t, ok)
+
+	assert.Equal(t, -10, d.PopFront())
+	assert.Equal(t, 10, d.PopBack())
+	assert.Equal(t, []int{-9, -8, -7}, ReadSeq[[]int](TakeSeq(d.Seq(), 3)))
+
+	for d.Len() > 2 {
+		d.PopBack()
+	}
+	d.PushBack(100)
+	assert.Equal(t, []int{-9, -8, 100}, d.Slice())
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+	assert.Empty(t, d.Slice())
+}
+
+func TestAlternativeContainersForTopoSort(t *testing.T) {
+	graph := Graph[int]{
+		1: {3, 4},
+		2: {5},
+		3: nil,
+		4: nil,
+		5: nil,
+	}
+
+	queue := graph.TopoSort(NewDequeAsContainer[int])
+	assertTopoOrder(t, graph, queue)
+	assert.Len(t, queue, 5)
+
+	order := graph.TopoSort(func(capacity int) Container[int] {
+		return NewIndexedHeapAsContainer(Greater[int], capacity)
+	})
+	assert.Equal(t, []int{2, 5, 1, 4, 3}, order)
+
+	var c Container[string] = NewDequeAsContainer[string](1)
+	c.Push("a")
+	c.Push("b")
+	assert.Equal(t, "a", c.Peek())
+	assert.Equal(t, "a", c.Pop())
+	assert.Equal(t, "b", c.Pop())
+	assert.True(t, c.IsEmpty())
+}
@@ -8,6 +8,7 @@
 package stl
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -64,3 +65,153 @@ func (errors Errors) Error() string {
 	})
 	return strings.Join(chips, "\n")
 }
+
+// Unwrap 返回其中的非 nil 错误，使 errors.Is / errors.As 可以逐个检查
+func (errors Errors) Unwrap() []error {
+	return errors.PurgeZero()
+}
+
+// MarshalJSON 编码为 ErrorTree
+func (errors Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewErrorTree(errors))
+}
+
+// StructuredError 为错误附加错误码与字段，错误信息与被包装的错误相同
+type StructuredError struct {
+	err    error
+	code   int
+	fields map[string]any
+}
+
+// WrapErrorCode 为 err 附加错误码，err 为 nil 时返回 nil
+func WrapErrorCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &StructuredError{err: err, code: code}
+}
+
+// WrapErrorFields 为 err 附加字段，err 为 nil 时返回 nil
+func WrapErrorFields(err error, fields map[string]any) error {
+	if err == nil {
+		return nil
+	}
+	return &StructuredError{err: err, fields: DupMap(fields)}
+}
+
+func (err *StructuredError) Error() string {
+	return err.err.Error()
+}
+
+func (err *StructuredError) Unwrap() error {
+	return err.err
+}
+
+func (err *StructuredError) Code() int {
+	return err.code
+}
+
+func (err *StructuredError) Fields() map[string]any {
+	return err.fields
+}
+
+func (err *StructuredError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewErrorTree(err))
+}
+
+// ErrorCodeOf 沿包装链查找第一个非零错误码，多错误时深度优先
+func ErrorCodeOf(err error) (code int, ok bool) {
+	walkErrorChain(err, func(err error) bool {
+		if structured, is := err.(*StructuredError); is && structured.code != 0 {
+			code, ok = structured.code, true
+		}
+		return !ok
+	})
+	return
+}
+
+// ErrorFieldsOf 沿单一包装链合并字段，外层的同名字段优先；遇到多错误时停止
+func ErrorFieldsOf(err error) map[string]any {
+	var fields map[string]any
+	for ; err != nil; err = unwrapSingleError(err) {
+		if structured, ok := err.(*StructuredError); ok {
+			for key, value := range structured.fields {
+				if fields == nil {
+					fields = map[string]any{}
+				}
+				CacheMapValue(fields, key, value)
+			}
+		}
+	}
+	return fields
+}
+
+func unwrapSingleError(err error) error {
+	if wrapper, ok := err.(interface{ Unwrap() error }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
+// walkErrorChain 深度优先遍历错误树，handle 返回 false 时停止
+func walkErrorChain(err error, handle func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !handle(err) {
+		return false
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrorChain(wrapper.Unwrap(), handle)
+	case interface{ Unwrap() []error }:
+		for _, child := range wrapper.Unwrap() {
+			if !walkErrorChain(child, handle) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ErrorTree 为错误树的 JSON 表示：单一包装链折叠为一个节点，错误码与字段取自链上的 StructuredError；
+// 多错误（Errors、errors.Join 等）的每个错误为一个子节点。字段按键排序编码，结果稳定。
+type ErrorTree struct {
+	Message string         `json:"message"`
+	Code    int            `json:"code,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+	Errors  []*ErrorTree   `json:"errors,omitempty"`
+}
+
+func NewErrorTree(err error) *ErrorTree {
+	if err == nil {
+		return nil
+	}
+
+	tree := &ErrorTree{
+		Message: err.Error(),
+		Fields:  ErrorFieldsOf(err),
+	}
+
+	// Errors 的信息由各子错误拼接而成，不再重复
+	if errs, ok := err.(Errors); ok {
+		tree.Message = fmt.Sprintf("%d errors", len(errs.PurgeZero()))
+	}
+
+	for cur := err; cur != nil; cur = unwrapSingleError(cur) {
+		if structured, ok := cur.(*StructuredError); ok && tree.Code == 0 {
+			tree.Code = structured.code
+		}
+
+		if multiple, ok := cur.(interface{ Unwrap() []error }); ok {
+			children := Filter(multiple.Unwrap(), func(child error) bool { return child != nil })
+			tree.Errors = Map(children, NewErrorTree)
+			break
+		}
+	}
+
+	return tree
+}
@@ -0,0 +1,74 @@
+package stl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsUnwrap(t *testing.T) {
+	errBoom := errors.New("boom")
+	errs := NewErrors(nil, fmt.Errorf("fetch: %w", basic.NewTimeoutError("fetch")), nil, errBoom)
+
+	assert.Equal(t, 2, len(errs.Unwrap()))
+	assert.ErrorIs(t, errs, errBoom)
+
+	var timeout *basic.TimeoutError
+	assert.True(t, errors.As(errs, &timeout))
+	assert.Equal(t, "fetch timeout", timeout.Error())
+
+	// 嵌套的 Errors 也能遍历
+	assert.ErrorIs(t, fmt.Errorf("outer: %w", NewErrors(NewErrors(errBoom))), errBoom)
+}
+
+func TestStructuredError(t *testing.T) {
+	errBoom := errors.New("boom")
+	assert.Nil(t, WrapErrorCode(nil, 1))
+	assert.Nil(t, WrapErrorFields(nil, nil))
+
+	err := WrapErrorFields(WrapErrorCode(WrapErrorFields(errBoom, map[string]any{"id": 1, "db": "a"}), 404), map[string]any{"id": 2})
+	assert.Equal(t, "boom", err.Error())
+	assert.ErrorIs(t, err, errBoom)
+
+	code, ok := ErrorCodeOf(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, 404, code)
+	assert.Equal(t, map[string]any{"id": 2, "db": "a"}, ErrorFieldsOf(err))
+
+	_, ok = ErrorCodeOf(errBoom)
+	assert.False(t, ok)
+
+	code, ok = ErrorCodeOf(NewErrors(errBoom, WrapErrorCode(errBoom, 500)))
+	assert.True(t, ok)
+	assert.Equal(t, 500, code)
+}
+
+func TestErrorsMarshalJSON(t *testing.T) {
+	errs := NewErrors(
+		WrapErrorFields(WrapErrorCode(errors.New("not found"), 404), map[string]any{"name": "x", "id": 1}),
+		nil,
+		fmt.Errorf("batch: %w", errors.Join(errors.New("a"), errors.New("b"))),
+	)
+
+	data, err := json.Marshal(errs)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"message": "2 errors",
+		"errors": [
+			{"message": "not found", "code": 404, "fields": {"id": 1, "name": "x"}},
+			{"message": "batch: a\nb", "errors": [{"message": "a"}, {"message": "b"}]}
+		]
+	}`, string(data))
+
+	// 字段按键排序，多次编码结果一致
+	again, _ := json.Marshal(errs)
+	assert.Equal(t, string(data), string(again))
+
+	data, err = json.Marshal(WrapErrorCode(errors.New("x"), 1))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"message":"x","code":1}`, string(data))
+}
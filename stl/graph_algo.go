@@ -0,0 +1,348 @@
+package stl
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// GraphCycleError 在图中存在环、无法完成拓扑排序时返回，Cycles 为每个环上的节点路径，
+// 路径最后一个节点有边指回第一个节点。
+type GraphCycleError[T comparable] struct {
+	Cycles [][]T
+}
+
+func NewGraphCycleError[T comparable](cycles [][]T) *GraphCycleError[T] {
+	return &GraphCycleError[T]{
+		Cycles: cycles,
+	}
+}
+
+func (err *GraphCycleError[T]) Error() string {
+	chips := Map(err.Cycles, func(cycle []T) string {
+		nodes := Map(append(DupSlice(cycle), cycle[0]), func(node T) string {
+			return fmt.Sprint(node)
+		})
+		return strings.Join(nodes, " -> ")
+	})
+	return fmt.Sprintf("graph has %d cycle(s): %s", len(chips), strings.Join(chips, "; "))
+}
+
+// Nodes 返回图中全部节点，包括只作为邻居出现的节点。
+// 节点排好序返回（数值按大小，字符串及其他类型按 fmt.Sprint 的结果），使依赖它的算法结果可复现。
+func (g Graph[T]) Nodes() []T {
+	nodes := make([]T, 0, len(g))
+	seen := make(Set[T], len(g))
+
+	add := func(node T) {
+		if !seen.Contain(node) {
+			seen.Add(node)
+			nodes = append(nodes, node)
+		}
+	}
+
+	for node, neighbors := range g {
+		add(node)
+		ForEach(neighbors, add)
+	}
+
+	slices.SortStableFunc(nodes, compareGraphNodes[T])
+	return nodes
+}
+
+func compareGraphNodes[T comparable](a, b T) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		case reflect.String:
+			return cmp.Compare(va.String(), vb.String())
+		}
+	}
+
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// Reverse 返回所有边反向后的图。
+func (g Graph[T]) Reverse() Graph[T] {
+	reversed := make(Graph[T], len(g))
+	for _, node := range g.Nodes() {
+		neighbors, ok := g[node]
+		if !ok {
+			continue
+		}
+
+		if _, ok := reversed[node]; !ok {
+			reversed[node] = nil
+		}
+
+		for _, neighbor := range neighbors {
+			reversed[neighbor] = append(reversed[neighbor], node)
+		}
+	}
+	return reversed
+}
+
+// TopoSortChecked 同 TopoSort，但图中存在环时返回 *GraphCycleError 以及已排序的部分。
+func (g Graph[T]) TopoSortChecked(container func(capacity int) Container[T]) ([]T, error) {
+	result := g.TopoSort(container)
+	if len(result) == len(g.Nodes()) {
+		return result, nil
+	}
+
+	return result, NewGraphCycleError(g.Cycles())
+}
+
+// TopoSortLayersChecked 同 TopoSortLayers，但图中存在环时返回 *GraphCycleError 以及已分层的部分。
+func (g Graph[T]) TopoSortLayersChecked(container func(capacity int) Container[T]) ([][]T, error) {
+	layers := g.TopoSortLayers(container)
+
+	total := 0
+	for _, layer := range layers {
+		total += len(layer)
+	}
+
+	if total == len(g.Nodes()) {
+		return layers, nil
+	}
+
+	return layers, NewGraphCycleError(g.Cycles())
+}
+
+// StronglyConnectedComponents 使用 Tarjan 算法求强连通分量，分量按逆拓扑序返回（无出边的分量在前）。
+func (g Graph[T]) StronglyConnectedComponents() [][]T {
+	index := 0
+	indexes := make(map[T]int)
+	lowlinks := make(map[T]int)
+	onStack := make(Set[T])
+	stack := NewStack[T](0)
+
+	var components [][]T
+
+	var connect func(node T)
+	connect = func(node T) {
+		indexes[node] = index
+		lowlinks[node] = index
+		index++
+
+		stack.Push(node)
+		onStack.Add(node)
+
+		for _, neighbor := range g[node] {
+			if _, visited := indexes[neighbor]; !visited {
+				connect(neighbor)
+				lowlinks[node] = min(lowlinks[node], lowlinks[neighbor])
+			} else if onStack.Contain(neighbor) {
+				lowlinks[node] = min(lowlinks[node], indexes[neighbor])
+			}
+		}
+
+		if lowlinks[node] != indexes[node] {
+			return
+		}
+
+		var component []T
+		for {
+			member := stack.Pop()
+			onStack.Pop(member)
+			component = append(component, member)
+			if member == node {
+				break
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	for _, node := range g.Nodes() {
+		if _, visited := indexes[node]; !visited {
+			connect(node)
+		}
+	}
+
+	return components
+}
+
+// Cycles 为每个含环的强连通分量返回一条环路径（经过分量中某个节点的最短环）。
+func (g Graph[T]) Cycles() [][]T {
+	var cycles [][]T
+	for _, component := range g.StronglyConnectedComponents() {
+		if cycle := g.cycleIn(NewSet(component...), component[0]); cycle != nil {
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+func (g Graph[T]) HasCycle() bool {
+	return len(g.Cycles()) > 0
+}
+
+// cycleIn 在 members 限定的子图中广度优先查找从 start 出发回到 start 的最短路径
+func (g Graph[T]) cycleIn(members Set[T], start T) []T {
+	parents := make(map[T]T)
+	queue := []T{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, neighbor := range g[node] {
+			if !members.Contain(neighbor) {
+				continue
+			}
+
+			if neighbor == start {
+				cycle := []T{node}
+				for node != start {
+					node = parents[node]
+					cycle = append(cycle, node)
+				}
+				return Reverse(cycle)
+			}
+
+			if _, seen := parents[neighbor]; seen {
+				continue
+			}
+
+			parents[neighbor] = node
+			queue = append(queue, neighbor)
+		}
+	}
+
+	return nil
+}
+
+// Reachable 返回从 from 出发经过至少一条边可达的节点；from 在环上时包含其自身。
+func (g Graph[T]) Reachable(from T) Set[T] {
+	reachable := make(Set[T])
+	for node := range g.bfs(g[from]) {
+		reachable.Add(node)
+	}
+	return reachable
+}
+
+func (g Graph[T]) CanReach(from, to T) bool {
+	for node := range g.bfs(g[from]) {
+		if node == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitiveClosure 返回传递闭包：u 可达 v 时存在边 u -> v。
+func (g Graph[T]) TransitiveClosure() Graph[T] {
+	closure := make(Graph[T], len(g))
+	for _, node := range g.Nodes() {
+		closure[node] = ReadSeq[[]T](g.bfs(g[node]))
+	}
+	return closure
+}
+
+// TransitiveReduction 返回传递归约：删除所有可由其他路径推出的边，可达关系保持不变。
+// 仅对有向无环图有定义，存在环时返回 *GraphCycleError。
+func (g Graph[T]) TransitiveReduction() (Graph[T], error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, NewGraphCycleError(cycles)
+	}
+
+	reduction := make(Graph[T], len(g))
+	for _, node := range g.Nodes() {
+		neighbors := StableUniqueBySet(g[node])
+
+		// 经由其他直接邻居可达的节点，其直连边都是冗余的
+		indirect := make(Set[T])
+		for _, neighbor := range neighbors {
+			for reachable := range g.bfs(g[neighbor]) {
+				indirect.Add(reachable)
+			}
+		}
+
+		reduction[node] = Filter(neighbors, func(neighbor T) bool {
+			return !indirect.Contain(neighbor)
+		})
+	}
+
+	return reduction, nil
+}
+
+// BFSSeq 从 starts 出发广度优先遍历，每个节点只产出一次；starts 为空时从全部节点出发。
+func (g Graph[T]) BFSSeq(starts ...T) iter.Seq[T] {
+	if len(starts) == 0 {
+		starts = g.Nodes()
+	}
+	return g.bfs(starts)
+}
+
+// bfs 同 BFSSeq，但 starts 为空时不产出任何节点
+func (g Graph[T]) bfs(starts []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		visited := make(Set[T])
+		queue := make([]T, 0, len(starts))
+		for _, start := range starts {
+			if !visited.Contain(start) {
+				visited.Add(start)
+				queue = append(queue, start)
+			}
+		}
+
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+
+			if !yield(node) {
+				return
+			}
+
+			for _, neighbor := range g[node] {
+				if !visited.Contain(neighbor) {
+					visited.Add(neighbor)
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+}
+
+// DFSSeq 从 starts 出发深度优先遍历（先序），每个节点只产出一次；starts 为空时从全部节点出发。
+func (g Graph[T]) DFSSeq(starts ...T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if len(starts) == 0 {
+			starts = g.Nodes()
+		}
+
+		visited := make(Set[T])
+		stack := NewStack[T](0)
+		for _, start := range Reverse(DupSlice(starts)) {
+			stack.Push(start)
+		}
+
+		for !stack.IsEmpty() {
+			node := stack.Pop()
+			if visited.Contain(node) {
+				continue
+			}
+
+			visited.Add(node)
+			if !yield(node) {
+				return
+			}
+
+			neighbors := g[node]
+			for i := len(neighbors) - 1; i >= 0; i-- {
+				if !visited.Contain(neighbors[i]) {
+					stack.Push(neighbors[i])
+				}
+			}
+		}
+	}
+}
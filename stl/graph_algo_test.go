@@ -0,0 +1,122 @@
+package stl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedComponents(components [][]string) [][]string {
+	for _, component := range components {
+		SortFast(component)
+	}
+	return Sort(components, func(a, b []string) bool {
+		return a[0] < b[0]
+	})
+}
+
+func TestGraphCycles(t *testing.T) {
+	graph := Graph[string]{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a", "d"},
+		"d": {"e"},
+		"e": {"e"},
+		"f": {"d"},
+	}
+
+	assert.True(t, graph.HasCycle())
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"d"}, {"e"}, {"f"}}, sortedComponents(graph.StronglyConnectedComponents()))
+
+	// 节点有序遍历，结果可复现
+	assert.Equal(t, [][]string{{"e"}, {"d"}, {"c", "b", "a"}, {"f"}}, graph.StronglyConnectedComponents())
+
+	cycles := graph.Cycles()
+	assert.Equal(t, [][]string{{"e"}, {"c", "a", "b"}}, cycles)
+	for _, cycle := range cycles {
+		for i, node := range cycle {
+			assert.Contains(t, graph[node], cycle[(i+1)%len(cycle)])
+		}
+	}
+
+	order, err := graph.TopoSortChecked(nil)
+	assert.Equal(t, []string{"f"}, order)
+
+	cycleErr, ok := err.(*GraphCycleError[string])
+	assert.True(t, ok)
+	assert.Len(t, cycleErr.Cycles, 2)
+	assert.Contains(t, err.Error(), "e -> e")
+
+	_, err = graph.TopoSortLayersChecked(nil)
+	assert.NotNil(t, err)
+}
+
+func TestGraphCycleErrorMessage(t *testing.T) {
+	graph := Graph[string]{
+		"a": {"b"},
+		"b": {"a"},
+	}
+
+	_, err := graph.TopoSortChecked(nil)
+	assert.EqualError(t, err, "graph has 1 cycle(s): b -> a -> b")
+}
+
+func TestGraphAcyclic(t *testing.T) {
+	graph := Graph[int]{
+		1: {2, 3, 4},
+		2: {4},
+		3: {4},
+		4: {5},
+	}
+
+	assert.False(t, graph.HasCycle())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, graph.Nodes())
+	assert.Equal(t, []int{2, 9, 10}, Graph[int]{10: {9}, 2: nil}.Nodes())
+
+	order, err := graph.TopoSortChecked(nil)
+	assert.Nil(t, err)
+	assertTopoOrder(t, graph, order)
+
+	layers, err := graph.TopoSortLayersChecked(NewMinHeapAsContainer[int])
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1}, {2, 3}, {4}, {5}}, layers)
+
+	assert.Equal(t, NewSet(2, 3, 4, 5), graph.Reachable(1))
+	assert.Equal(t, NewSet(5), graph.Reachable(4))
+	assert.True(t, graph.CanReach(2, 5))
+	assert.False(t, graph.CanReach(5, 1))
+	assert.False(t, graph.CanReach(1, 1))
+
+	closure := graph.TransitiveClosure()
+	assert.ElementsMatch(t, []int{2, 3, 4, 5}, closure[1])
+	assert.ElementsMatch(t, []int{4, 5}, closure[3])
+	assert.Empty(t, closure[5])
+
+	reduction, err := graph.TransitiveReduction()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 3}, reduction[1])
+	assert.Equal(t, []int{4}, reduction[2])
+	assert.Equal(t, []int{5}, reduction[4])
+
+	_, err = Graph[int]{1: {1}}.TransitiveReduction()
+	assert.NotNil(t, err)
+}
+
+func TestGraphTraversal(t *testing.T) {
+	graph := Graph[int]{
+		1: {2, 3},
+		2: {4},
+		3: {4, 5},
+		4: {1},
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ReadSeq[[]int](graph.BFSSeq(1)))
+	assert.Equal(t, []int{1, 2, 4, 3, 5}, ReadSeq[[]int](graph.DFSSeq(1)))
+	assert.Equal(t, []int{3, 4, 5, 1, 2}, ReadSeq[[]int](graph.BFSSeq(3)))
+	assert.Equal(t, []int{1, 2}, ReadSeq[[]int](TakeSeq(graph.DFSSeq(1), 2)))
+	assert.Equal(t, []int{1, 2, 4, 3, 5}, ReadSeq[[]int](graph.DFSSeq()))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ReadSeq[[]int](graph.BFSSeq()))
+
+	reversed := graph.Reverse()
+	assert.Equal(t, []int{4, 2, 3, 1}, ReadSeq[[]int](reversed.BFSSeq(4)))
+}
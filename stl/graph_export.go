@@ -0,0 +1,524 @@
+package stl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GraphRenderer 将 Graph 渲染为 Graphviz DOT 或 Mermaid flowchart 文本，输出顺序稳定，便于比对。
+type GraphRenderer[T comparable] struct {
+	graph Graph[T]
+
+	name            string
+	direction       string
+	id              func(T) string
+	label           func(T) string
+	layers          [][]T
+	highlightCycles bool
+}
+
+func (g Graph[T]) BuildRenderer() *GraphRenderer[T] {
+	return &GraphRenderer[T]{
+		graph: g,
+		name:  "G",
+		id: func(node T) string {
+			return fmt.Sprint(node)
+		},
+	}
+}
+
+func (renderer *GraphRenderer[T]) WithName(name string) *GraphRenderer[T] {
+	renderer.name = name
+	return renderer
+}
+
+// WithDirection 设置布局方向，如 TB、LR
+func (renderer *GraphRenderer[T]) WithDirection(direction string) *GraphRenderer[T] {
+	renderer.direction = direction
+	return renderer
+}
+
+// WithIdFunc 设置节点标识，默认为 fmt.Sprint；不同节点的标识须不同
+func (renderer *GraphRenderer[T]) WithIdFunc(id func(T) string) *GraphRenderer[T] {
+	renderer.id = id
+	return renderer
+}
+
+// WithLabelFunc 设置节点显示文本，未设置时显示节点标识
+func (renderer *GraphRenderer[T]) WithLabelFunc(label func(T) string) *GraphRenderer[T] {
+	renderer.label = label
+	return renderer
+}
+
+// WithLayers 按层分组节点，通常传入 TopoSortLayers 的结果
+func (renderer *GraphRenderer[T]) WithLayers(layers [][]T) *GraphRenderer[T] {
+	renderer.layers = layers
+	return renderer
+}
+
+// WithCycleHighlight 标红环上的节点和边
+func (renderer *GraphRenderer[T]) WithCycleHighlight(highlight bool) *GraphRenderer[T] {
+	renderer.highlightCycles = highlight
+	return renderer
+}
+
+// nodes 先按层输出，其余节点按标识排序
+func (renderer *GraphRenderer[T]) nodes() []T {
+	var nodes []T
+	layered := make(Set[T])
+	for _, layer := range renderer.layers {
+		for _, node := range layer {
+			if !layered.Contain(node) {
+				layered.Add(node)
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	rest := Filter(renderer.graph.Nodes(), func(node T) bool {
+		return !layered.Contain(node)
+	})
+	Sort(rest, func(a, b T) bool {
+		return renderer.id(a) < renderer.id(b)
+	})
+
+	return append(nodes, rest...)
+}
+
+// cyclic 返回位于环上的节点所属的强连通分量编号
+func (renderer *GraphRenderer[T]) cyclic() map[T]int {
+	components := make(map[T]int)
+	if !renderer.highlightCycles {
+		return components
+	}
+
+	for i, component := range renderer.graph.StronglyConnectedComponents() {
+		if len(component) == 1 && !Contain(renderer.graph[component[0]], component[0]) {
+			continue
+		}
+
+		for _, node := range component {
+			components[node] = i
+		}
+	}
+
+	return components
+}
+
+func (renderer *GraphRenderer[T]) onCycle(components map[T]int, from, to T) bool {
+	fromComponent, ok := components[from]
+	if !ok {
+		return false
+	}
+
+	toComponent, ok := components[to]
+	return ok && fromComponent == toComponent
+}
+
+func quoteDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func (renderer *GraphRenderer[T]) RenderDOT() string {
+	var builder strings.Builder
+
+	nodes := renderer.nodes()
+	components := renderer.cyclic()
+
+	fmt.Fprintf(&builder, "digraph %s {\n", quoteDOT(renderer.name))
+	if renderer.direction != "" {
+		fmt.Fprintf(&builder, "  rankdir=%s;\n", renderer.direction)
+	}
+
+	for _, node := range nodes {
+		var attrs []string
+		if renderer.label != nil {
+			attrs = append(attrs, "label="+quoteDOT(renderer.label(node)))
+		}
+		if _, ok := components[node]; ok {
+			attrs = append(attrs, "color=red")
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&builder, "  %s;\n", quoteDOT(renderer.id(node)))
+		} else {
+			fmt.Fprintf(&builder, "  %s [%s];\n", quoteDOT(renderer.id(node)), strings.Join(attrs, ", "))
+		}
+	}
+
+	for i, layer := range renderer.layers {
+		fmt.Fprintf(&builder, "  subgraph cluster_layer%d {\n", i)
+		fmt.Fprintf(&builder, "    label=\"layer %d\";\n", i)
+		for _, node := range layer {
+			fmt.Fprintf(&builder, "    %s;\n", quoteDOT(renderer.id(node)))
+		}
+		builder.WriteString("  }\n")
+	}
+
+	for _, node := range nodes {
+		for _, neighbor := range renderer.graph[node] {
+			edge := fmt.Sprintf("%s -> %s", quoteDOT(renderer.id(node)), quoteDOT(renderer.id(neighbor)))
+			if renderer.onCycle(components, node, neighbor) {
+				edge += " [color=red]"
+			}
+			fmt.Fprintf(&builder, "  %s;\n", edge)
+		}
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func quoteMermaid(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
+
+// RenderMermaid 输出 Mermaid flowchart，节点以 n0、n1 等编号，显示文本为标签或标识
+func (renderer *GraphRenderer[T]) RenderMermaid() string {
+	var builder strings.Builder
+
+	nodes := renderer.nodes()
+	components := renderer.cyclic()
+
+	ids := make(map[T]string, len(nodes))
+	for i, node := range nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+	}
+
+	direction := renderer.direction
+	if direction == "" {
+		direction = "TD"
+	}
+	fmt.Fprintf(&builder, "flowchart %s\n", direction)
+
+	for _, node := range nodes {
+		text := renderer.id(node)
+		if renderer.label != nil {
+			text = renderer.label(node)
+		}
+		fmt.Fprintf(&builder, "  %s[%s]\n", ids[node], quoteMermaid(text))
+	}
+
+	for i, layer := range renderer.layers {
+		fmt.Fprintf(&builder, "  subgraph layer%d [\"layer %d\"]\n", i, i)
+		for _, node := range layer {
+			fmt.Fprintf(&builder, "    %s\n", ids[node])
+		}
+		builder.WriteString("  end\n")
+	}
+
+	var cycleNodes []string
+	var cycleLinks []string
+
+	link := 0
+	for _, node := range nodes {
+		if _, ok := components[node]; ok {
+			cycleNodes = append(cycleNodes, ids[node])
+		}
+
+		for _, neighbor := range renderer.graph[node] {
+			fmt.Fprintf(&builder, "  %s --> %s\n", ids[node], ids[neighbor])
+			if renderer.onCycle(components, node, neighbor) {
+				cycleLinks = append(cycleLinks, fmt.Sprint(link))
+			}
+			link++
+		}
+	}
+
+	if len(cycleNodes) > 0 {
+		builder.WriteString("  classDef cycle stroke:#f00,color:#f00\n")
+		fmt.Fprintf(&builder, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&builder, "  linkStyle %s stroke:#f00\n", strings.Join(cycleLinks, ","))
+	}
+
+	return builder.String()
+}
+
+// GraphFromDOT 解析 RenderDOT 输出的 DOT 子集：digraph、节点语句、边语句（支持 a -> b -> c 链式写法）、
+// 属性赋值和 subgraph。返回的图包含全部节点，以及带 label 属性的节点标签。
+func GraphFromDOT(text string) (Graph[string], map[string]string, error) {
+	tokens, err := tokenizeDOT(text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parser := &dotParser{
+		tokens: tokens,
+		graph:  make(Graph[string]),
+		labels: make(map[string]string),
+	}
+
+	if err := parser.parse(); err != nil {
+		return nil, nil, err
+	}
+
+	return parser.graph, parser.labels, nil
+}
+
+type dotToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeDOT(text string) ([]dotToken, error) {
+	var tokens []dotToken
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '/' && strings.HasPrefix(text[i:], "//"), c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			var builder strings.Builder
+			i++
+			for {
+				if i >= len(text) {
+					return nil, fmt.Errorf("dot: unterminated string")
+				}
+				if text[i] == '"' {
+					i++
+					break
+				}
+				if text[i] == '\\' && i+1 < len(text) {
+					switch text[i+1] {
+					case 'n':
+						builder.WriteByte('\n')
+					case '"', '\\':
+						builder.WriteByte(text[i+1])
+					default:
+						builder.WriteByte('\\')
+						builder.WriteByte(text[i+1])
+					}
+					i += 2
+					continue
+				}
+				builder.WriteByte(text[i])
+				i++
+			}
+			tokens = append(tokens, dotToken{text: builder.String(), quoted: true})
+		case strings.HasPrefix(text[i:], "->"):
+			tokens = append(tokens, dotToken{text: "->"})
+			i += 2
+		case strings.ContainsRune("{}[]=;,", rune(c)):
+			tokens = append(tokens, dotToken{text: string(c)})
+			i++
+		case isDOTIdentByte(c):
+			start := i
+			for i < len(text) && isDOTIdentByte(text[i]) {
+				i++
+			}
+			tokens = append(tokens, dotToken{text: text[start:i]})
+		default:
+			return nil, fmt.Errorf("dot: unexpected character %q at offset %d", c, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isDOTIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+var dotPunctuations = NewSet("{", "}", "[", "]", "=", ";", ",", "->")
+
+type dotParser struct {
+	tokens []dotToken
+	pos    int
+
+	graph  Graph[string]
+	labels map[string]string
+}
+
+func (parser *dotParser) peek() (dotToken, bool) {
+	if parser.pos >= len(parser.tokens) {
+		return dotToken{}, false
+	}
+	return parser.tokens[parser.pos], true
+}
+
+func (parser *dotParser) next() (dotToken, error) {
+	token, ok := parser.peek()
+	if !ok {
+		return token, fmt.Errorf("dot: unexpected end of input")
+	}
+	parser.pos++
+	return token, nil
+}
+
+func (parser *dotParser) expect(text string) error {
+	token, err := parser.next()
+	if err != nil {
+		return err
+	}
+	if token.quoted || token.text != text {
+		return fmt.Errorf("dot: expect %q, got %q", text, token.text)
+	}
+	return nil
+}
+
+func (parser *dotParser) is(text string) bool {
+	token, ok := parser.peek()
+	return ok && !token.quoted && token.text == text
+}
+
+func (parser *dotParser) id() (string, error) {
+	token, err := parser.next()
+	if err != nil {
+		return "", err
+	}
+	if !token.quoted && dotPunctuations.Contain(token.text) {
+		return "", fmt.Errorf("dot: expect identifier, got %q", token.text)
+	}
+	return token.text, nil
+}
+
+func (parser *dotParser) parse() error {
+	if parser.is("strict") {
+		parser.pos++
+	}
+
+	if err := parser.expect("digraph"); err != nil {
+		return err
+	}
+
+	if !parser.is("{") {
+		if _, err := parser.id(); err != nil {
+			return err
+		}
+	}
+
+	if err := parser.block(); err != nil {
+		return err
+	}
+
+	if token, ok := parser.peek(); ok {
+		return fmt.Errorf("dot: unexpected %q after graph", token.text)
+	}
+
+	return nil
+}
+
+func (parser *dotParser) block() error {
+	if err := parser.expect("{"); err != nil {
+		return err
+	}
+
+	for !parser.is("}") {
+		if err := parser.statement(); err != nil {
+			return err
+		}
+
+		for parser.is(";") || parser.is(",") {
+			parser.pos++
+		}
+	}
+
+	return parser.expect("}")
+}
+
+func (parser *dotParser) statement() error {
+	if parser.is("subgraph") {
+		parser.pos++
+		if !parser.is("{") {
+			if _, err := parser.id(); err != nil {
+				return err
+			}
+		}
+		return parser.block()
+	}
+
+	if parser.is("{") {
+		return parser.block()
+	}
+
+	if parser.is("graph") || parser.is("node") || parser.is("edge") {
+		parser.pos++
+		_, err := parser.attrs()
+		return err
+	}
+
+	first, err := parser.id()
+	if err != nil {
+		return err
+	}
+
+	if parser.is("=") {
+		parser.pos++
+		_, err := parser.id()
+		return err
+	}
+
+	nodes := []string{first}
+	for parser.is("->") {
+		parser.pos++
+		node, err := parser.id()
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, node)
+	}
+
+	attrs, err := parser.attrs()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if _, ok := parser.graph[node]; !ok {
+			parser.graph[node] = nil
+		}
+	}
+
+	if len(nodes) == 1 {
+		if label, ok := attrs["label"]; ok {
+			parser.labels[first] = label
+		}
+		return nil
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		parser.graph[nodes[i-1]] = append(parser.graph[nodes[i-1]], nodes[i])
+	}
+
+	return nil
+}
+
+func (parser *dotParser) attrs() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for parser.is("[") {
+		parser.pos++
+		for !parser.is("]") {
+			key, err := parser.id()
+			if err != nil {
+				return nil, err
+			}
+			if err := parser.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := parser.id()
+			if err != nil {
+				return nil, err
+			}
+			attrs[key] = value
+
+			for parser.is(",") || parser.is(";") {
+				parser.pos++
+			}
+		}
+		parser.pos++
+	}
+	return attrs, nil
+}
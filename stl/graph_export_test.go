@@ -0,0 +1,152 @@
+package stl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphRenderDOT(t *testing.T) {
+	graph := Graph[string]{
+		"a": {"b", "c"},
+		"b": {"c"},
+		"c": nil,
+	}
+
+	dot := graph.BuildRenderer().
+		WithName("deps").
+		WithDirection("LR").
+		WithLabelFunc(strings.ToUpper).
+		WithLayers(graph.TopoSortLayers(NewMinHeapAsContainer[string])).
+		RenderDOT()
+
+	assert.Equal(t, `digraph "deps" {
+  rankdir=LR;
+  "a" [label="A"];
+  "b" [label="B"];
+  "c" [label="C"];
+  subgraph cluster_layer0 {
+    label="layer 0";
+    "a";
+  }
+  subgraph cluster_layer1 {
+    label="layer 1";
+    "b";
+  }
+  subgraph cluster_layer2 {
+    label="layer 2";
+    "c";
+  }
+  "a" -> "b";
+  "a" -> "c";
+  "b" -> "c";
+}
+`, dot)
+
+	parsed, labels, err := GraphFromDOT(dot)
+	assert.Nil(t, err)
+	assert.Equal(t, graph, parsed)
+	assert.Equal(t, map[string]string{"a": "A", "b": "B", "c": "C"}, labels)
+}
+
+func TestGraphRenderCycleHighlight(t *testing.T) {
+	graph := Graph[int]{
+		1: {2},
+		2: {3},
+		3: {2, 4},
+		4: {4},
+	}
+
+	dot := graph.BuildRenderer().WithCycleHighlight(true).RenderDOT()
+	assert.Equal(t, `digraph "G" {
+  "1";
+  "2" [color=red];
+  "3" [color=red];
+  "4" [color=red];
+  "1" -> "2";
+  "2" -> "3" [color=red];
+  "3" -> "2" [color=red];
+  "3" -> "4";
+  "4" -> "4" [color=red];
+}
+`, dot)
+
+	mermaid := graph.BuildRenderer().WithCycleHighlight(true).RenderMermaid()
+	assert.Equal(t, `flowchart TD
+  n0["1"]
+  n1["2"]
+  n2["3"]
+  n3["4"]
+  n0 --> n1
+  n1 --> n2
+  n2 --> n1
+  n2 --> n3
+  n3 --> n3
+  classDef cycle stroke:#f00,color:#f00
+  class n1,n2,n3 cycle
+  linkStyle 1,2,4 stroke:#f00
+`, mermaid)
+
+	parsed, _, err := GraphFromDOT(dot)
+	assert.Nil(t, err)
+	assert.Equal(t, Graph[string]{"1": {"2"}, "2": {"3"}, "3": {"2", "4"}, "4": {"4"}}, parsed)
+}
+
+func TestGraphRenderMermaidLayers(t *testing.T) {
+	graph := Graph[string]{
+		"x": {"y"},
+		"y": nil,
+	}
+
+	mermaid := graph.BuildRenderer().
+		WithDirection("LR").
+		WithLabelFunc(func(node string) string { return `say "` + node + `"` }).
+		WithLayers([][]string{{"x"}, {"y"}}).
+		RenderMermaid()
+
+	assert.Equal(t, `flowchart LR
+  n0["say #quot;x#quot;"]
+  n1["say #quot;y#quot;"]
+  subgraph layer0 ["layer 0"]
+    n0
+  end
+  subgraph layer1 ["layer 1"]
+    n1
+  end
+  n0 --> n1
+`, mermaid)
+}
+
+func TestGraphFromDOT(t *testing.T) {
+	graph, labels, err := GraphFromDOT(`
+		// hand written fixture
+		strict digraph {
+			node [shape=box];
+			a -> b -> c [color=blue];
+			"with \"quote\"" [label="line\nbreak", shape=ellipse]
+			subgraph { rank=same; b; d }
+			a -> d
+		}
+	`)
+	assert.Nil(t, err)
+	assert.Equal(t, Graph[string]{
+		"a":            {"b", "d"},
+		"b":            {"c"},
+		"c":            nil,
+		"d":            nil,
+		`with "quote"`: nil,
+	}, graph)
+	assert.Equal(t, map[string]string{`with "quote"`: "line\nbreak"}, labels)
+
+	for _, text := range []string{
+		`graph { a -- b }`,
+		`digraph { a -> }`,
+		`digraph { "a }`,
+		`digraph { a [label] }`,
+		`digraph { a } b`,
+	} {
+		_, _, err := GraphFromDOT(text)
+		assert.NotNil(t, err, text)
+	}
+}
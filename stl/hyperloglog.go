@@ -0,0 +1,131 @@
+package stl
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	HyperLogLogMinPrecision = 4
+	HyperLogLogMaxPrecision = 18
+)
+
+// HyperLogLog 以 2^precision 字节估计不同键的个数，标准误差约为 1.04/sqrt(2^precision)。
+type HyperLogLog[Key comparable] struct {
+	precision uint8
+	registers []uint8
+	hasher    Hasher[Key]
+}
+
+// NewHyperLogLog 创建精度为 precision 的估计器，precision 被限制在 [4, 18] 内
+func NewHyperLogLog[Key comparable](precision int) *HyperLogLog[Key] {
+	precision = min(max(precision, HyperLogLogMinPrecision), HyperLogLogMaxPrecision)
+	return &HyperLogLog[Key]{
+		precision: uint8(precision),
+		registers: make([]uint8, 1<<precision),
+		hasher:    DefaultHasher[Key],
+	}
+}
+
+func (hll *HyperLogLog[Key]) WithHasher(hasher Hasher[Key]) *HyperLogLog[Key] {
+	hll.hasher = hasher
+	return hll
+}
+
+func (hll *HyperLogLog[Key]) Precision() int {
+	return int(hll.precision)
+}
+
+func (hll *HyperLogLog[Key]) Add(key Key) {
+	if hll.hasher == nil {
+		hll.hasher = DefaultHasher[Key]
+	}
+
+	h := hll.hasher(key)
+	index := h >> (64 - hll.precision)
+	rank := uint8(bits.LeadingZeros64(h<<hll.precision|1<<(hll.precision-1))) + 1
+
+	if rank > hll.registers[index] {
+		hll.registers[index] = rank
+	}
+}
+
+// Count 返回不同键个数的估计值
+func (hll *HyperLogLog[Key]) Count() uint64 {
+	m := float64(len(hll.registers))
+
+	sum := 0.0
+	zeros := 0
+	for _, register := range hll.registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(hll.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// 小基数时使用线性计数修正
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Merge 合并另一个同精度的估计器，结果等价于对两者的键的并集计数
+func (hll *HyperLogLog[Key]) Merge(other *HyperLogLog[Key]) error {
+	if hll.precision != other.precision {
+		return ErrSketchMismatch
+	}
+
+	for i, register := range other.registers {
+		hll.registers[i] = max(hll.registers[i], register)
+	}
+
+	return nil
+}
+
+func (hll *HyperLogLog[Key]) Reset() {
+	clear(hll.registers)
+}
+
+const hyperLogLogMagic = "hll\x01"
+
+func (hll *HyperLogLog[Key]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(hyperLogLogMagic)+1+len(hll.registers))
+	data = append(data, hyperLogLogMagic...)
+	data = append(data, hll.precision)
+	data = append(data, hll.registers...)
+	return data, nil
+}
+
+// UnmarshalBinary 恢复精度与寄存器，Hasher 保持不变（未设置时为 DefaultHasher）
+func (hll *HyperLogLog[Key]) UnmarshalBinary(data []byte) error {
+	reader := newSketchReader(data, hyperLogLogMagic)
+
+	precision := reader.bytes(1)
+	if reader.err != nil || precision[0] < HyperLogLogMinPrecision || precision[0] > HyperLogLogMaxPrecision {
+		return ErrSketchCorrupted
+	}
+
+	registers := reader.bytes(1 << precision[0])
+	if err := reader.finish(); err != nil {
+		return err
+	}
+
+	hll.precision = precision[0]
+	hll.registers = append([]uint8(nil), registers...)
+	return nil
+}
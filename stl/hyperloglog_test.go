@@ -0,0 +1,53 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertApproximately(t *testing.T, expected, actual uint64, ratio float64) {
+	t.Helper()
+	assert.LessOrEqual(t, math.Abs(float64(actual)-float64(expected)), float64(expected)*ratio, "expected ~%d, got %d", expected, actual)
+}
+
+func TestHyperLogLog(t *testing.T) {
+	hll := NewHyperLogLog[int](14)
+	assert.Equal(t, uint64(0), hll.Count())
+
+	for i := 0; i < 10; i++ {
+		hll.Add(i)
+		hll.Add(i)
+	}
+	assert.Equal(t, uint64(10), hll.Count())
+
+	for i := 0; i < 100000; i++ {
+		hll.Add(i)
+	}
+	assertApproximately(t, 100000, hll.Count(), 0.03)
+
+	other := NewHyperLogLog[int](14)
+	for i := 50000; i < 150000; i++ {
+		other.Add(i)
+	}
+	assert.Nil(t, hll.Merge(other))
+	assertApproximately(t, 150000, hll.Count(), 0.03)
+	assert.Equal(t, ErrSketchMismatch, hll.Merge(NewHyperLogLog[int](10)))
+
+	data, err := hll.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Len(t, data, 5+1<<14)
+
+	var restored HyperLogLog[int]
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, hll.Count(), restored.Count())
+	assert.Equal(t, 14, restored.Precision())
+
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(data[:100]))
+
+	assert.Equal(t, HyperLogLogMaxPrecision, NewHyperLogLog[int](30).Precision())
+
+	hll.Reset()
+	assert.Equal(t, uint64(0), hll.Count())
+}
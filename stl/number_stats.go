@@ -0,0 +1,179 @@
+package stl
+
+import (
+	"math"
+	"sort"
+)
+
+// Variance 返回总体方差，空时为 0
+func (numbers Numbers[Data]) Variance() float64 {
+	if numbers.Empty() {
+		return 0
+	}
+
+	// Welford 算法，避免先求平方和再相减带来的精度损失
+	var mean, m2 float64
+	for i, number := range numbers {
+		delta := float64(number) - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (float64(number) - mean)
+	}
+
+	return m2 / float64(numbers.Len())
+}
+
+// SampleVariance 返回样本方差（除以 n-1），个数不足 2 时为 0
+func (numbers Numbers[Data]) SampleVariance() float64 {
+	n := numbers.Len()
+	if n < 2 {
+		return 0
+	}
+	return numbers.Variance() * float64(n) / float64(n-1)
+}
+
+// StdDev 返回总体标准差
+func (numbers Numbers[Data]) StdDev() float64 {
+	return math.Sqrt(numbers.Variance())
+}
+
+// SampleStdDev 返回样本标准差
+func (numbers Numbers[Data]) SampleStdDev() float64 {
+	return math.Sqrt(numbers.SampleVariance())
+}
+
+// Mean 以 float64 返回平均值，整数类型不会像 Avg 那样截断
+func (numbers Numbers[Data]) Mean() float64 {
+	if numbers.Empty() {
+		return 0
+	}
+
+	var sum float64
+	for _, number := range numbers {
+		sum += float64(number)
+	}
+
+	return sum / float64(numbers.Len())
+}
+
+// Median 返回中位数，空时为 0
+func (numbers Numbers[Data]) Median() float64 {
+	return numbers.Percentile(50)
+}
+
+// Percentile 返回第 p 百分位数（p 取 0~100），相邻两数之间线性插值；不修改原切片，空时为 0
+func (numbers Numbers[Data]) Percentile(p float64) float64 {
+	return numbers.Percentiles(p)[0]
+}
+
+// Percentiles 一次排序求多个百分位数
+func (numbers Numbers[Data]) Percentiles(ps ...float64) []float64 {
+	results := make([]float64, len(ps))
+	if numbers.Empty() {
+		return results
+	}
+
+	sorted := numbers.Dup().Sort()
+	for i, p := range ps {
+		results[i] = sortedPercentile(sorted, p)
+	}
+
+	return results
+}
+
+func sortedPercentile[Data Number](sorted Numbers[Data], p float64) float64 {
+	rank := min(max(p, 0), 100) / 100 * float64(sorted.Len()-1)
+	lower := int(math.Floor(rank))
+	upper := min(lower+1, sorted.Len()-1)
+
+	fraction := rank - float64(lower)
+	return float64(sorted[lower]) + fraction*(float64(sorted[upper])-float64(sorted[lower]))
+}
+
+// Histogram 按升序边界 bounds 统计各区间的个数，结果长度为 len(bounds)+1：
+// 第 0 个区间为 (-∞, bounds[0])，第 i 个区间为 [bounds[i-1], bounds[i])，最后一个区间为 [bounds[len-1], +∞)
+func (numbers Numbers[Data]) Histogram(bounds ...Data) []int {
+	counts := make([]int, len(bounds)+1)
+	for _, number := range numbers {
+		counts[sort.Search(len(bounds), func(i int) bool { return bounds[i] > number })]++
+	}
+	return counts
+}
+
+// MovingAvg 返回窗口大小为 window 的滑动平均，第 i 个结果为截止到第 i 个数的最近 window 个数的平均值
+func (numbers Numbers[Data]) MovingAvg(window int) Numbers[float64] {
+	ma := NewMovingAverage[Data](window)
+	return Map(numbers, ma.Add)
+}
+
+// LinearBuckets 生成从 start 开始、间隔 width 的 count 个直方图边界
+func LinearBuckets[Data Number](start, width Data, count int) Numbers[Data] {
+	bounds := make(Numbers[Data], 0, max(count, 0))
+	for i := 0; i < count; i++ {
+		bounds = append(bounds, start+width*Data(i))
+	}
+	return bounds
+}
+
+// ExponentialBuckets 生成从 start 开始、每个是前一个 factor 倍的 count 个直方图边界
+func ExponentialBuckets[Data Number](start, factor Data, count int) Numbers[Data] {
+	bounds := make(Numbers[Data], 0, max(count, 0))
+	for bound, i := start, 0; i < count; bound, i = bound*factor, i+1 {
+		bounds = append(bounds, bound)
+	}
+	return bounds
+}
+
+// MovingAverage 流式计算最近 size 个数的平均值
+type MovingAverage[Data Number] struct {
+	window Numbers[Data]
+	size   int
+	next   int
+	sum    float64
+}
+
+func NewMovingAverage[Data Number](size int) *MovingAverage[Data] {
+	size = max(size, 1)
+	return &MovingAverage[Data]{
+		window: make(Numbers[Data], 0, size),
+		size:   size,
+	}
+}
+
+// Add 加入一个数并返回当前平均值
+func (ma *MovingAverage[Data]) Add(number Data) float64 {
+	if ma.window.Len() < ma.size {
+		ma.window = append(ma.window, number)
+		ma.sum += float64(number)
+		return ma.Avg()
+	}
+
+	ma.sum += float64(number) - float64(ma.window[ma.next])
+	ma.window[ma.next] = number
+	ma.next = (ma.next + 1) % ma.size
+
+	// 每轮重新求和，避免浮点误差累积
+	if ma.next == 0 {
+		ma.sum = ma.window.Mean() * float64(ma.size)
+	}
+
+	return ma.Avg()
+}
+
+// Avg 返回当前平均值，没有数据时为 0
+func (ma *MovingAverage[Data]) Avg() float64 {
+	if ma.window.Empty() {
+		return 0
+	}
+	return ma.sum / float64(ma.window.Len())
+}
+
+// Len 返回窗口内的个数
+func (ma *MovingAverage[Data]) Len() int {
+	return ma.window.Len()
+}
+
+func (ma *MovingAverage[Data]) Reset() {
+	ma.window = ma.window[:0]
+	ma.next = 0
+	ma.sum = 0
+}
@@ -0,0 +1,59 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumbersStats(t *testing.T) {
+	numbers := Numbers[int]{2, 4, 4, 4, 5, 5, 7, 9}
+	assert.Equal(t, 5.0, numbers.Mean())
+	assert.Equal(t, 4.0, numbers.Variance())
+	assert.Equal(t, 2.0, numbers.StdDev())
+	assert.InDelta(t, 32.0/7, numbers.SampleVariance(), 1e-9)
+	assert.InDelta(t, math.Sqrt(32.0/7), numbers.SampleStdDev(), 1e-9)
+
+	assert.Equal(t, 0.0, Numbers[int]{}.Variance())
+	assert.Equal(t, 0.0, Numbers[int]{1}.SampleVariance())
+}
+
+func TestNumbersPercentile(t *testing.T) {
+	numbers := Numbers[int]{5, 1, 4, 2, 3}
+	assert.Equal(t, 3.0, numbers.Median())
+	assert.Equal(t, 1.0, numbers.Percentile(0))
+	assert.Equal(t, 5.0, numbers.Percentile(100))
+	assert.Equal(t, 2.0, numbers.Percentile(25))
+	assert.Equal(t, []float64{1.4, 4.6}, numbers.Percentiles(10, 90))
+	assert.Equal(t, Numbers[int]{5, 1, 4, 2, 3}, numbers)
+
+	assert.Equal(t, 2.5, Numbers[int]{1, 2, 3, 4}.Median())
+	assert.Equal(t, 0.0, Numbers[int]{}.Median())
+}
+
+func TestNumbersHistogram(t *testing.T) {
+	numbers := Numbers[int]{0, 1, 5, 10, 11, 50, 100, 1000}
+	assert.Equal(t, []int{2, 1, 2, 1, 2}, numbers.Histogram(5, 10, 50, 100))
+	assert.Equal(t, []int{8}, numbers.Histogram())
+
+	assert.Equal(t, Numbers[int]{10, 20, 30}, LinearBuckets(10, 10, 3))
+	assert.Equal(t, Numbers[float64]{1, 2, 4, 8}, ExponentialBuckets(1.0, 2, 4))
+}
+
+func TestMovingAverage(t *testing.T) {
+	numbers := Numbers[int]{1, 2, 3, 4, 5, 6}
+	assert.Equal(t, Numbers[float64]{1, 1.5, 2, 3, 4, 5}, numbers.MovingAvg(3))
+
+	ma := NewMovingAverage[float64](2)
+	assert.Equal(t, 0.0, ma.Avg())
+	for i := range 1000 {
+		ma.Add(float64(i) / 10)
+	}
+	assert.Equal(t, 2, ma.Len())
+	assert.InDelta(t, 99.85, ma.Avg(), 1e-9)
+
+	ma.Reset()
+	assert.Equal(t, 0, ma.Len())
+	assert.Equal(t, 4.0, ma.Add(4))
+}
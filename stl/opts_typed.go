@@ -0,0 +1,353 @@
+package stl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	ErrOptionRequired      = errors.New("stl: option value is required")
+	ErrOptionInvalidChoice = errors.New("stl: option value is not a valid choice")
+)
+
+// OptionLookup 按名称查找选项的原始值，一名多值（如 url.Values）时返回全部
+type OptionLookup func(name string) (values []string, ok bool)
+
+// NewOptionLookupFromValues 从一名多值的映射查找，如 url.Values、http.Header
+func NewOptionLookupFromValues[Values ~map[string][]string](values Values) OptionLookup {
+	return func(name string) ([]string, bool) {
+		result, ok := values[name]
+		return result, ok && len(result) > 0
+	}
+}
+
+// NewOptionLookupFromMap 从普通映射查找，如 osx.EnvironMap
+func NewOptionLookupFromMap[Map ~map[string]string](m Map) OptionLookup {
+	return func(name string) ([]string, bool) {
+		value, ok := m[name]
+		if !ok {
+			return nil, false
+		}
+		return []string{value}, true
+	}
+}
+
+// NewOptionLookupFromFunc 从查找函数查找，如 os.LookupEnv、osx.EnvironMap.GetLooker
+func NewOptionLookupFromFunc(lookup func(string) (string, bool)) OptionLookup {
+	return func(name string) ([]string, bool) {
+		value, ok := lookup(name)
+		if !ok {
+			return nil, false
+		}
+		return []string{value}, true
+	}
+}
+
+// WithNameMapper 查找前先用 mapper 转换选项名，如 EnvOptionName
+func (lookup OptionLookup) WithNameMapper(mapper func(string) string) OptionLookup {
+	return func(name string) ([]string, bool) {
+		return lookup(mapper(name))
+	}
+}
+
+// EnvOptionName 将选项名转换为环境变量名：加上前缀，转为大写，- 与 . 替换为 _，如 page-size 转换为 APP_PAGE_SIZE
+func EnvOptionName(prefix string) func(string) string {
+	replacer := strings.NewReplacer("-", "_", ".", "_")
+	return func(name string) string {
+		return strings.ToUpper(replacer.Replace(prefix + name))
+	}
+}
+
+// OptionError 为单个选项的解析或校验错误
+type OptionError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (err *OptionError) Error() string {
+	if err.Value == "" {
+		return fmt.Sprintf("option %s: %s", err.Name, err.Err)
+	}
+	return fmt.Sprintf("option %s=%q: %s", err.Name, err.Value, err.Err)
+}
+
+func (err *OptionError) Unwrap() error {
+	return err.Err
+}
+
+// OptionHelp 描述一个选项，用于生成帮助信息
+type OptionHelp struct {
+	Name     string
+	Type     string
+	Usage    string
+	Required bool
+	Default  string
+	Choices  []string
+}
+
+func (help OptionHelp) Notes() string {
+	var notes []string
+	if help.Required {
+		notes = append(notes, "required")
+	} else if help.Default != "" {
+		notes = append(notes, "default: "+help.Default)
+	}
+
+	if len(help.Choices) > 0 {
+		notes = append(notes, "choices: "+strings.Join(help.Choices, "|"))
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+
+	return "(" + strings.Join(notes, ", ") + ")"
+}
+
+// OptionParser 从 OptionLookup 中解析出一个选项，没有值且不必填时返回 nil
+type OptionParser[Data any] interface {
+	ParseOption(lookup OptionLookup) (Option[Data], error)
+	OptionHelp() OptionHelp
+}
+
+type OptionParsers[Data any] []OptionParser[Data]
+
+// Parse 解析全部选项，各选项的错误汇总为 Errors 返回
+func (parsers OptionParsers[Data]) Parse(lookup OptionLookup) (Options[Data], error) {
+	opts := make(Options[Data], 0, len(parsers))
+	var errs Errors
+
+	for _, parser := range parsers {
+		opt, err := parser.ParseOption(lookup)
+		if err != nil {
+			errs = errs.Append(err)
+		} else if opt != nil {
+			opts = opts.Append(opt)
+		}
+	}
+
+	if err := errs.Simplify(); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// Help 生成对齐的帮助信息，每个选项一行
+func (parsers OptionParsers[Data]) Help() string {
+	var builder strings.Builder
+
+	writer := tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
+	for _, parser := range parsers {
+		help := parser.OptionHelp()
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", help.Name, help.Type, strings.TrimSpace(help.Usage+" "+help.Notes()))
+	}
+	writer.Flush()
+
+	// 没有说明的行会留下对齐用的空格
+	var result strings.Builder
+	for line := range strings.Lines(builder.String()) {
+		result.WriteString(strings.TrimRight(line, " \n"))
+		result.WriteByte('\n')
+	}
+
+	return result.String()
+}
+
+// ParseAndAppendOptions 从 lookup 解析选项并追加
+func (opts Options[Data]) ParseAndAppendOptions(lookup OptionLookup, parsers OptionParsers[Data]) (Options[Data], error) {
+	more, err := parsers.Parse(lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.Append(more...), nil
+}
+
+// TypedOptionMeta 描述一个类型为 Value 的选项：如何解析、校验，以及如何转换为 Option
+type TypedOptionMeta[Data any, Value any] struct {
+	name         string
+	typeName     string
+	usage        string
+	parse        func(raws []string) (Value, error)
+	opt          func(Value) Option[Data]
+	validators   []func(Value) error
+	required     bool
+	defaultValue *Value
+	choices      []string
+}
+
+// NewTypedOptionMeta 创建选项元信息，parse 解析单个原始值，一名多值时只取第一个
+func NewTypedOptionMeta[Data any, Value any](name, typeName string, parse func(string) (Value, error), opt func(Value) Option[Data]) *TypedOptionMeta[Data, Value] {
+	return &TypedOptionMeta[Data, Value]{
+		name:     name,
+		typeName: typeName,
+		parse: func(raws []string) (Value, error) {
+			return parse(raws[0])
+		},
+		opt: opt,
+	}
+}
+
+func NewStringOptionMeta[Data any](name string, opt func(string) Option[Data]) *TypedOptionMeta[Data, string] {
+	return NewTypedOptionMeta(name, "string", func(raw string) (string, error) {
+		return raw, nil
+	}, opt)
+}
+
+func NewBoolOptionMeta[Data any](name string, opt func(bool) Option[Data]) *TypedOptionMeta[Data, bool] {
+	return NewTypedOptionMeta(name, "bool", strconv.ParseBool, opt)
+}
+
+// NewNumberOptionMeta 创建整数或浮点数选项，超出 Value 范围或整数类型遇到小数时报错
+func NewNumberOptionMeta[Data any, Value Number](name string, opt func(Value) Option[Data]) *TypedOptionMeta[Data, Value] {
+	return NewTypedOptionMeta(name, fmt.Sprintf("%T", Value(0)), ParseNumber[Value], opt)
+}
+
+func NewDurationOptionMeta[Data any](name string, opt func(time.Duration) Option[Data]) *TypedOptionMeta[Data, time.Duration] {
+	return NewTypedOptionMeta(name, "duration", time.ParseDuration, opt)
+}
+
+// NewEnumOptionMeta 创建枚举选项，值须为 choices 之一
+func NewEnumOptionMeta[Data any, Value ~string](name string, choices []Value, opt func(Value) Option[Data]) *TypedOptionMeta[Data, Value] {
+	meta := NewTypedOptionMeta(name, "enum", func(raw string) (Value, error) {
+		if !Contain(choices, Value(raw)) {
+			return "", ErrOptionInvalidChoice
+		}
+		return Value(raw), nil
+	}, opt)
+	meta.choices = Map(choices, func(choice Value) string { return string(choice) })
+	return meta
+}
+
+// NewListOptionMeta 创建列表选项：一名多值时全部采用，每个值再按 separator 切分（separator 为空时不切分）
+func NewListOptionMeta[Data any, Value any](name, separator string, parse func(string) (Value, error), opt func([]Value) Option[Data]) *TypedOptionMeta[Data, []Value] {
+	meta := NewTypedOptionMeta[Data, []Value](name, "list", nil, opt)
+	meta.parse = func(raws []string) ([]Value, error) {
+		var values []Value
+		for _, raw := range raws {
+			items := []string{raw}
+			if separator != "" {
+				items = strings.Split(raw, separator)
+			}
+
+			for _, item := range items {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+
+				value, err := parse(item)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+		}
+		return values, nil
+	}
+	return meta
+}
+
+func (meta *TypedOptionMeta[Data, Value]) WithUsage(usage string) *TypedOptionMeta[Data, Value] {
+	meta.usage = usage
+	return meta
+}
+
+func (meta *TypedOptionMeta[Data, Value]) WithRequired(required bool) *TypedOptionMeta[Data, Value] {
+	meta.required = required
+	return meta
+}
+
+// WithDefault 设置没有值时使用的默认值，默认值不经过校验
+func (meta *TypedOptionMeta[Data, Value]) WithDefault(value Value) *TypedOptionMeta[Data, Value] {
+	meta.defaultValue = &value
+	return meta
+}
+
+// WithValidator 追加校验函数，解析成功后依次校验
+func (meta *TypedOptionMeta[Data, Value]) WithValidator(validator func(Value) error) *TypedOptionMeta[Data, Value] {
+	meta.validators = append(meta.validators, validator)
+	return meta
+}
+
+func (meta *TypedOptionMeta[Data, Value]) Name() string {
+	return meta.name
+}
+
+func (meta *TypedOptionMeta[Data, Value]) ParseOption(lookup OptionLookup) (Option[Data], error) {
+	raws, ok := lookup(meta.name)
+	if !ok || AllMatch(raws, func(raw string) bool { return raw == "" }) {
+		if meta.required {
+			return nil, &OptionError{Name: meta.name, Err: ErrOptionRequired}
+		}
+
+		if meta.defaultValue != nil {
+			return meta.opt(*meta.defaultValue), nil
+		}
+
+		return nil, nil
+	}
+
+	value, err := meta.parse(raws)
+	if err != nil {
+		return nil, &OptionError{Name: meta.name, Value: strings.Join(raws, ","), Err: err}
+	}
+
+	for _, validator := range meta.validators {
+		if err := validator(value); err != nil {
+			return nil, &OptionError{Name: meta.name, Value: strings.Join(raws, ","), Err: err}
+		}
+	}
+
+	return meta.opt(value), nil
+}
+
+func (meta *TypedOptionMeta[Data, Value]) OptionHelp() OptionHelp {
+	help := OptionHelp{
+		Name:     meta.name,
+		Type:     meta.typeName,
+		Usage:    meta.usage,
+		Required: meta.required,
+		Choices:  meta.choices,
+	}
+
+	if meta.defaultValue != nil {
+		help.Default = fmt.Sprint(*meta.defaultValue)
+	}
+
+	return help
+}
+
+// ParseNumber 将字符串解析为 Value 类型的数，超出范围或整数类型遇到小数时报错
+func ParseNumber[Value Number](raw string) (Value, error) {
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		if value := Value(i); int64(value) == i && (i < 0) == (value < 0) {
+			return value, nil
+		}
+		return 0, fmt.Errorf("%w: %s out of range for %T", strconv.ErrRange, raw, Value(0))
+	}
+
+	if u, err := strconv.ParseUint(raw, 10, 64); err == nil {
+		if value := Value(u); uint64(value) == u && value >= 0 {
+			return value, nil
+		}
+		return 0, fmt.Errorf("%w: %s out of range for %T", strconv.ErrRange, raw, Value(0))
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	value := Value(f)
+	if float64(value) != f && float64(float32(f)) != float64(value) {
+		return 0, fmt.Errorf("%w: %s is not a valid %T", strconv.ErrSyntax, raw, Value(0))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,157 @@
+package stl
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedOptionsTarget struct {
+	Name    string
+	Verbose bool
+	Size    int
+	Ratio   float64
+	Timeout time.Duration
+	Mode    string
+	Tags    []string
+	Ports   []int
+}
+
+type typedOptionsMode string
+
+func typedOptionsParsers() OptionParsers[*typedOptionsTarget] {
+	return OptionParsers[*typedOptionsTarget]{
+		NewStringOptionMeta("name", func(value string) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Name = value }
+		}).WithUsage("service name").WithRequired(true),
+		NewBoolOptionMeta("verbose", func(value bool) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Verbose = value }
+		}),
+		NewNumberOptionMeta("size", func(value int) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Size = value }
+		}).WithDefault(10).WithValidator(func(value int) error {
+			if value <= 0 {
+				return errors.New("must be positive")
+			}
+			return nil
+		}),
+		NewNumberOptionMeta("ratio", func(value float64) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Ratio = value }
+		}),
+		NewDurationOptionMeta("timeout", func(value time.Duration) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Timeout = value }
+		}).WithDefault(time.Second),
+		NewEnumOptionMeta("mode", []typedOptionsMode{"fast", "safe"}, func(value typedOptionsMode) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Mode = string(value) }
+		}),
+		NewListOptionMeta("tag", ",", func(raw string) (string, error) { return raw, nil }, func(values []string) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Tags = values }
+		}),
+		NewListOptionMeta("port", ",", strconv.Atoi, func(values []int) Option[*typedOptionsTarget] {
+			return func(target *typedOptionsTarget) { target.Ports = values }
+		}),
+	}
+}
+
+func TestTypedOptionsFromValues(t *testing.T) {
+	values := url.Values{
+		"name":    {"api"},
+		"verbose": {"true"},
+		"ratio":   {"0.5"},
+		"mode":    {"safe"},
+		"tag":     {"a,b", "c"},
+		"port":    {"80, 443"},
+	}
+
+	opts, err := NewOptions[*typedOptionsTarget]().ParseAndAppendOptions(NewOptionLookupFromValues(values), typedOptionsParsers())
+	assert.Nil(t, err)
+
+	target := opts.Apply(&typedOptionsTarget{})
+	assert.Equal(t, &typedOptionsTarget{
+		Name:    "api",
+		Verbose: true,
+		Size:    10,
+		Ratio:   0.5,
+		Timeout: time.Second,
+		Mode:    "safe",
+		Tags:    []string{"a", "b", "c"},
+		Ports:   []int{80, 443},
+	}, target)
+}
+
+func TestTypedOptionsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"APP_NAME":    "worker",
+		"APP_SIZE":    "3",
+		"APP_TIMEOUT": "1m",
+	}
+	lookup := NewOptionLookupFromFunc(func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}).WithNameMapper(EnvOptionName("app-"))
+
+	opts, err := typedOptionsParsers().Parse(lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, &typedOptionsTarget{Name: "worker", Size: 3, Timeout: time.Minute}, opts.Apply(&typedOptionsTarget{}))
+}
+
+func TestTypedOptionsErrors(t *testing.T) {
+	lookup := NewOptionLookupFromMap(map[string]string{
+		"verbose": "maybe",
+		"size":    "-1",
+		"mode":    "slow",
+		"port":    "80,http",
+	})
+
+	opts, err := typedOptionsParsers().Parse(lookup)
+	assert.Nil(t, opts)
+
+	var errs Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 5, errs.Len())
+
+	names := Map(errs, func(err error) string {
+		var optErr *OptionError
+		assert.True(t, errors.As(err, &optErr))
+		return optErr.Name
+	})
+	assert.Equal(t, []string{"name", "verbose", "size", "mode", "port"}, names)
+	assert.True(t, errors.Is(errs[0], ErrOptionRequired))
+	assert.True(t, errors.Is(errs[3], ErrOptionInvalidChoice))
+	assert.Contains(t, errs[2].Error(), "must be positive")
+}
+
+func TestTypedOptionsHelp(t *testing.T) {
+	lines := strings.Split(strings.TrimRight(typedOptionsParsers().Help(), "\n"), "\n")
+	assert.Equal(t, 8, len(lines))
+	assert.Equal(t, "  name     string    service name (required)", lines[0])
+	assert.Equal(t, "  size     int       (default: 10)", lines[2])
+	assert.Equal(t, "  ratio    float64", lines[3])
+	assert.Equal(t, "  mode     enum      (choices: fast|safe)", lines[5])
+}
+
+func TestParseNumber(t *testing.T) {
+	i, err := ParseNumber[int8]("-12")
+	assert.Nil(t, err)
+	assert.Equal(t, int8(-12), i)
+
+	_, err = ParseNumber[int8]("300")
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	_, err = ParseNumber[uint]("-1")
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	_, err = ParseNumber[int]("1.5")
+	assert.NotNil(t, err)
+
+	f, err := ParseNumber[float32]("0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, float32(0.1), f)
+
+	u, err := ParseNumber[uint64]("18446744073709551615")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(18446744073709551615), u)
+}
@@ -0,0 +1,392 @@
+package stl
+
+import (
+	"bytes"
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// OrderedMapping 是按插入顺序遍历的映射，方法与 Mapping 一致。
+// 覆盖已有键的值不改变其位置；JSON 序列化与反序列化均保持键的顺序。
+// 零值可直接使用，非并发安全。
+type OrderedMapping[Key comparable, Value any] struct {
+	index   map[Key]*list.Element
+	entries *list.List
+}
+
+func NewOrderedMapping[Key comparable, Value any]() *OrderedMapping[Key, Value] {
+	return new(OrderedMapping[Key, Value]).init()
+}
+
+func NewOrderedMappingFromPairs[Key comparable, Value any](pairs KeyValuePairs[Key, Value]) *OrderedMapping[Key, Value] {
+	m := NewOrderedMapping[Key, Value]()
+	for _, pair := range pairs {
+		m.Store(pair.Key, pair.Value)
+	}
+	return m
+}
+
+func (m *OrderedMapping[Key, Value]) init() *OrderedMapping[Key, Value] {
+	if m.entries == nil {
+		m.index = make(map[Key]*list.Element)
+		m.entries = list.New()
+	}
+	return m
+}
+
+func (m *OrderedMapping[Key, Value]) entry(element *list.Element) *KeyValuePair[Key, Value] {
+	return element.Value.(*KeyValuePair[Key, Value])
+}
+
+func (m *OrderedMapping[Key, Value]) Clear() {
+	m.index = nil
+	m.entries = nil
+}
+
+func (m *OrderedMapping[Key, Value]) Delete(key Key) {
+	m.LoadAndDelete(key)
+}
+
+func (m *OrderedMapping[Key, Value]) Get(key Key) Value {
+	value, _ := m.Load(key)
+	return value
+}
+
+func (m *OrderedMapping[Key, Value]) Keys() []Key {
+	return ReadSeq[[]Key](m.KeySeq())
+}
+
+func (m *OrderedMapping[Key, Value]) KeyValuePairs() KeyValuePairs[Key, Value] {
+	pairs := make(KeyValuePairs[Key, Value], 0, m.Len())
+	for key, value := range m.KeyValueSeq() {
+		pairs = append(pairs, KeyValuePair[Key, Value]{Key: key, Value: value})
+	}
+	return pairs
+}
+
+func (m *OrderedMapping[Key, Value]) Len() int {
+	return len(m.index)
+}
+
+func (m *OrderedMapping[Key, Value]) Load(key Key) (value Value, ok bool) {
+	element, ok := m.index[key]
+	if !ok {
+		return
+	}
+	return m.entry(element).Value, true
+}
+
+func (m *OrderedMapping[Key, Value]) LoadAndDelete(key Key) (value Value, ok bool) {
+	element, ok := m.index[key]
+	if !ok {
+		return
+	}
+
+	delete(m.index, key)
+	m.entries.Remove(element)
+
+	return m.entry(element).Value, true
+}
+
+func (m *OrderedMapping[Key, Value]) LoadOrCreate(key Key, create func() Value) (Value, bool) {
+	value, ok := m.Load(key)
+	if ok {
+		return value, true
+	}
+
+	value = create()
+	m.Store(key, value)
+
+	return value, false
+}
+
+func (m *OrderedMapping[Key, Value]) LoadOrStore(key Key, value Value) (Value, bool) {
+	oldValue, ok := m.Load(key)
+	if ok {
+		return oldValue, true
+	}
+
+	m.Store(key, value)
+	return value, false
+}
+
+// Store 新键追加到末尾，已有键原地更新
+func (m *OrderedMapping[Key, Value]) Store(key Key, value Value) {
+	m.Swap(key, value)
+}
+
+func (m *OrderedMapping[Key, Value]) Swap(key Key, value Value) (previous Value, loaded bool) {
+	if element, ok := m.index[key]; ok {
+		entry := m.entry(element)
+		previous, entry.Value = entry.Value, value
+		return previous, true
+	}
+
+	m.init()
+	m.index[key] = m.entries.PushBack(NewKeyValuePair(key, value))
+
+	return
+}
+
+// StoreOk 同 Store，m 为 nil 时返回 false
+func (m *OrderedMapping[Key, Value]) StoreOk(key Key, value Value) (ok bool) {
+	if m == nil {
+		return false
+	}
+
+	m.Store(key, value)
+	return true
+}
+
+// SwapOk 同 Swap，m 为 nil 时 ok 为 false
+func (m *OrderedMapping[Key, Value]) SwapOk(key Key, value Value) (previous Value, loaded bool, ok bool) {
+	if m == nil {
+		return
+	}
+
+	previous, loaded = m.Swap(key, value)
+	return previous, loaded, true
+}
+
+func (m *OrderedMapping[Key, Value]) Values() []Value {
+	return ReadSeq[[]Value](m.ValueSeq())
+}
+
+// MoveToFront 将 key 移到最前，key 不存在时返回 false
+func (m *OrderedMapping[Key, Value]) MoveToFront(key Key) bool {
+	element, ok := m.index[key]
+	if ok {
+		m.entries.MoveToFront(element)
+	}
+	return ok
+}
+
+// MoveToBack 将 key 移到最后，key 不存在时返回 false
+func (m *OrderedMapping[Key, Value]) MoveToBack(key Key) bool {
+	element, ok := m.index[key]
+	if ok {
+		m.entries.MoveToBack(element)
+	}
+	return ok
+}
+
+// First 返回最前的键值对
+func (m *OrderedMapping[Key, Value]) First() (key Key, value Value, ok bool) {
+	if m.Len() == 0 {
+		return
+	}
+
+	entry := m.entry(m.entries.Front())
+	return entry.Key, entry.Value, true
+}
+
+// Last 返回最后的键值对
+func (m *OrderedMapping[Key, Value]) Last() (key Key, value Value, ok bool) {
+	if m.Len() == 0 {
+		return
+	}
+
+	entry := m.entry(m.entries.Back())
+	return entry.Key, entry.Value, true
+}
+
+// KeyValueSeq 按顺序遍历，遍历过程中可以删除当前键
+func (m *OrderedMapping[Key, Value]) KeyValueSeq() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if m.entries == nil {
+			return
+		}
+
+		for element := m.entries.Front(); element != nil; {
+			next := element.Next()
+
+			entry := m.entry(element)
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+
+			element = next
+		}
+	}
+}
+
+// BackwardKeyValueSeq 逆序遍历
+func (m *OrderedMapping[Key, Value]) BackwardKeyValueSeq() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if m.entries == nil {
+			return
+		}
+
+		for element := m.entries.Back(); element != nil; {
+			prev := element.Prev()
+
+			entry := m.entry(element)
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+
+			element = prev
+		}
+	}
+}
+
+func (m *OrderedMapping[Key, Value]) KeySeq() iter.Seq[Key] {
+	return KeySeqOf(m.KeyValueSeq())
+}
+
+func (m *OrderedMapping[Key, Value]) ValueSeq() iter.Seq[Value] {
+	return ValueSeqOf(m.KeyValueSeq())
+}
+
+// ToMapping 转为无序的 Mapping
+func (m *OrderedMapping[Key, Value]) ToMapping() Mapping[Key, Value] {
+	mapping := NewMappingWithCap[Key, Value](m.Len())
+	for key, value := range m.KeyValueSeq() {
+		mapping.Store(key, value)
+	}
+	return mapping
+}
+
+// MarshalJSON 按顺序输出 JSON 对象。键须为字符串、整数或实现 encoding.TextMarshaler，与 encoding/json 对 map 的要求一致。
+func (m *OrderedMapping[Key, Value]) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	i := 0
+	for key, value := range m.KeyValueSeq() {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		i++
+
+		text, err := marshalOrderedMappingKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		keyBytes, err := json.Marshal(text)
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(keyBytes)
+		buffer.WriteByte(':')
+		buffer.Write(valueBytes)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON 按 JSON 中出现的顺序存入，重复的键保留首次出现的位置、最后一次的值。
+// 已有内容会被清空；null 不做任何修改。
+func (m *OrderedMapping[Key, Value]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("stl: cannot unmarshal %v into OrderedMapping", token)
+	}
+
+	m.Clear()
+	m.init()
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := unmarshalOrderedMappingKey[Key](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value Value
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		m.Store(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// marshalOrderedMappingKey 与 encoding/json 一样，字符串类型的键直接使用其值，不调用 MarshalText
+func marshalOrderedMappingKey[Key comparable](key Key) (string, error) {
+	value := reflect.ValueOf(key)
+	if value.Kind() == reflect.String {
+		return value.String(), nil
+	}
+
+	if marshaler, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("stl: unsupported OrderedMapping key type %T", key)
+}
+
+// unmarshalOrderedMappingKey 与 marshalOrderedMappingKey 对应，字符串类型的键直接取原文，不调用 UnmarshalText
+func unmarshalOrderedMappingKey[Key comparable](text string) (key Key, err error) {
+	value := reflect.ValueOf(&key).Elem()
+	if value.Kind() == reflect.String {
+		value.SetString(text)
+		return
+	}
+
+	if unmarshaler, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err = unmarshaler.UnmarshalText([]byte(text))
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		value.SetUint(n)
+	default:
+		err = fmt.Errorf("stl: unsupported OrderedMapping key type %T", key)
+	}
+
+	return
+}
@@ -0,0 +1,149 @@
+package stl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMapping(t *testing.T) {
+	var m OrderedMapping[string, int]
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, m.Keys())
+
+	m.Store("c", 3)
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 30)
+	assert.Equal(t, []string{"c", "a", "b"}, m.Keys())
+	assert.Equal(t, []int{30, 1, 2}, m.Values())
+	assert.Equal(t, 30, m.Get("c"))
+
+	value, loaded := m.LoadOrStore("a", 10)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+
+	value, loaded = m.LoadOrCreate("d", func() int { return 4 })
+	assert.False(t, loaded)
+	assert.Equal(t, 4, value)
+
+	previous, loaded := m.Swap("b", 20)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, previous)
+
+	assert.True(t, m.MoveToFront("d"))
+	assert.True(t, m.MoveToBack("c"))
+	assert.False(t, m.MoveToBack("z"))
+	assert.Equal(t, KeyValuePairs[string, int]{{"d", 4}, {"a", 1}, {"b", 20}, {"c", 30}}, m.KeyValuePairs())
+	assert.Equal(t, KeyValuePairs[string, int]{{"c", 30}, {"b", 20}, {"a", 1}, {"d", 4}}, collectSeq2(m.BackwardKeyValueSeq()))
+
+	key, value, ok := m.First()
+	assert.True(t, ok)
+	assert.Equal(t, "d", key)
+	assert.Equal(t, 4, value)
+
+	key, _, ok = m.Last()
+	assert.True(t, ok)
+	assert.Equal(t, "c", key)
+
+	for key, value := range m.KeyValueSeq() {
+		if value >= 10 {
+			m.Delete(key)
+		}
+	}
+	assert.Equal(t, []string{"d", "a"}, m.Keys())
+	assert.Equal(t, Mapping[string, int]{"d": 4, "a": 1}, m.ToMapping())
+
+	value, ok = m.LoadAndDelete("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = m.Load("a")
+	assert.False(t, ok)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	_, _, ok = m.First()
+	assert.False(t, ok)
+}
+
+func TestOrderedMappingOk(t *testing.T) {
+	var m OrderedMapping[string, int]
+	assert.True(t, m.StoreOk("a", 1))
+
+	previous, loaded, ok := m.SwapOk("a", 10)
+	assert.True(t, ok)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, previous)
+
+	_, loaded, ok = m.SwapOk("b", 2)
+	assert.True(t, ok)
+	assert.False(t, loaded)
+	assert.Equal(t, []string{"a", "b"}, m.Keys())
+	assert.Equal(t, 10, m.Get("a"))
+
+	var nilMapping *OrderedMapping[string, int]
+	assert.False(t, nilMapping.StoreOk("a", 1))
+	_, _, ok = nilMapping.SwapOk("a", 1)
+	assert.False(t, ok)
+}
+
+func TestOrderedMappingJSON(t *testing.T) {
+	m := NewOrderedMappingFromPairs(KeyValuePairs[string, any]{{"zeta", 1}, {"alpha", "x"}, {"mid", []int{1, 2}}})
+
+	data, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"zeta":1,"alpha":"x","mid":[1,2]}`, string(data))
+
+	type Config struct {
+		Name   string
+		Fields *OrderedMapping[string, *OrderedMapping[int, string]]
+	}
+
+	var config Config
+	err = json.Unmarshal([]byte(`{"Name":"demo","Fields":{"b":{"3":"c","1":"a"},"a":null,"b2":{}}}`), &config)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "a", "b2"}, config.Fields.Keys())
+	assert.Equal(t, []int{3, 1}, config.Fields.Get("b").Keys())
+	assert.Nil(t, config.Fields.Get("a"))
+
+	data, err = json.Marshal(config)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Name":"demo","Fields":{"b":{"3":"c","1":"a"},"a":null,"b2":{}}}`, string(data))
+
+	var duplicated OrderedMapping[string, int]
+	assert.Nil(t, json.Unmarshal([]byte(`{"x":1,"y":2,"x":3}`), &duplicated))
+	assert.Equal(t, KeyValuePairs[string, int]{{"x", 3}, {"y", 2}}, duplicated.KeyValuePairs())
+
+	var numbers OrderedMapping[int, int]
+	assert.NotNil(t, json.Unmarshal([]byte(`{"x":1}`), &numbers))
+	assert.NotNil(t, json.Unmarshal([]byte(`[1]`), &numbers))
+
+	_, err = json.Marshal(NewOrderedMappingFromPairs(KeyValuePairs[float64, int]{{1.5, 1}}))
+	assert.NotNil(t, err)
+}
+
+type orderedMappingTextKey string
+
+func (key orderedMappingTextKey) MarshalText() ([]byte, error) {
+	return []byte("text-" + string(key)), nil
+}
+
+func (key *orderedMappingTextKey) UnmarshalText(text []byte) error {
+	*key = orderedMappingTextKey(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestOrderedMappingJSONStringKindKey(t *testing.T) {
+	// 字符串类型的键直接使用其值，不调用 MarshalText / UnmarshalText
+	m := NewOrderedMappingFromPairs(KeyValuePairs[orderedMappingTextKey, int]{{"b", 2}, {"a", 1}})
+
+	data, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"b":2,"a":1}`, string(data))
+
+	var restored OrderedMapping[orderedMappingTextKey, int]
+	assert.Nil(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, m.KeyValuePairs(), restored.KeyValuePairs())
+}
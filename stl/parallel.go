@@ -0,0 +1,175 @@
+package stl
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+
+	"github.com/fasionchan/goutils/basic"
+)
+
+type parallelResult[Result any] struct {
+	result Result
+	err    error
+}
+
+func parallelCall[Data any, Result any](ctx context.Context, mapper func(context.Context, Data) (Result, error), data Data) (result Result, err error) {
+	defer basic.RecoverPanic(&err)
+	return mapper(ctx, data)
+}
+
+func parallelConcurrency(concurrency int) int {
+	if concurrency <= 0 {
+		return runtime.NumCPU()
+	}
+	return concurrency
+}
+
+// ParallelMap 以至多 concurrency 个协程并发转换 datas，结果与输入顺序一致；concurrency <= 0 时取 CPU 数。
+// stopWhenError 为 true 时，首个错误会取消 ctx 并停止分发剩余数据，返回该错误；
+// 否则处理全部数据，按输入顺序将错误汇总为 Errors。mapper 中的 panic 转为 *basic.PanicError。
+func ParallelMap[Datas ~[]Data, Data any, Result any](
+	ctx context.Context,
+	datas Datas,
+	concurrency int,
+	stopWhenError bool,
+	mapper func(ctx context.Context, data Data) (Result, error),
+) ([]Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results := make([]Result, len(datas))
+	errs := make(Errors, len(datas))
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var firstErrOnce sync.Once
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(parallelConcurrency(concurrency), len(datas)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i], errs[i] = parallelCall(workerCtx, mapper, datas[i])
+				if errs[i] != nil && stopWhenError {
+					firstErrOnce.Do(func() {
+						firstErr = errs[i]
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feeding:
+	for i := range datas {
+		select {
+		case <-workerCtx.Done():
+			break feeding
+		case indexes <- i:
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, errs.Append(err).Simplify()
+	}
+
+	return results, errs.Simplify()
+}
+
+// ParallelMapSeq 与 ParallelMap 类似，但流式处理 seq：按输入顺序产出 (结果, 错误)，
+// 同时处理中的数据不超过 concurrency 个。stopWhenError 为 true 时，产出首个错误后结束；
+// ctx 被取消时产出 ctx.Err() 后结束。提前停止遍历会取消 ctx 并停止拉取 seq，
+// 遍历返回前会等待拉取 seq 及处理中的 mapper 结束。
+func ParallelMapSeq[Data any, Result any](
+	ctx context.Context,
+	seq iter.Seq[Data],
+	concurrency int,
+	stopWhenError bool,
+	mapper func(ctx context.Context, data Data) (Result, error),
+) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		parent := ctx
+		if parent == nil {
+			parent = context.Background()
+		}
+
+		workerCtx, cancel := context.WithCancel(parent)
+
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		// 消费方等待的 1 个加上缓冲中的 concurrency-1 个，恰好为 concurrency 个
+		slots := make(chan chan parallelResult[Result], parallelConcurrency(concurrency)-1)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(slots)
+
+			for data := range seq {
+				slot := make(chan parallelResult[Result], 1)
+				select {
+				case <-workerCtx.Done():
+					return
+				case slots <- slot:
+				}
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					result, err := parallelCall(workerCtx, mapper, data)
+					slot <- parallelResult[Result]{result: result, err: err}
+				}()
+			}
+		}()
+
+		for slot := range slots {
+			r := <-slot
+			if !yield(r.result, r.err) {
+				return
+			}
+
+			if r.err != nil && stopWhenError {
+				return
+			}
+		}
+
+		if err := parent.Err(); err != nil {
+			var zero Result
+			yield(zero, err)
+		}
+	}
+}
+
+// ParallelForEach 并发处理 datas，错误处理方式同 ParallelMap。
+func ParallelForEach[Datas ~[]Data, Data any](
+	ctx context.Context,
+	datas Datas,
+	concurrency int,
+	stopWhenError bool,
+	handler func(ctx context.Context, data Data) error,
+) error {
+	_, err := ParallelMap(ctx, datas, concurrency, stopWhenError, func(ctx context.Context, data Data) (struct{}, error) {
+		return struct{}{}, handler(ctx, data)
+	})
+	return err
+}
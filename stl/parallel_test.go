@@ -0,0 +1,168 @@
+package stl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelMapOrder(t *testing.T) {
+	var running, peak atomic.Int32
+
+	datas := ReadSeq[[]int](TakeSeq(naturalSeq, 50))
+	results, err := ParallelMap(context.Background(), datas, 4, true, func(ctx context.Context, i int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond * time.Duration(50-i) / 10)
+		return fmt.Sprint(i), nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, Map(datas, func(i int) string { return fmt.Sprint(i) }), results)
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+}
+
+func TestParallelMapStopWhenError(t *testing.T) {
+	var calls atomic.Int32
+	boom := errors.New("boom")
+
+	datas := ReadSeq[[]int](TakeSeq(naturalSeq, 100))
+	_, err := ParallelMap(context.Background(), datas, 2, true, func(ctx context.Context, i int) (int, error) {
+		calls.Add(1)
+		if i == 3 {
+			return 0, boom
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Millisecond * 10):
+			return i, nil
+		}
+	})
+
+	assert.Equal(t, boom, err)
+	assert.Less(t, calls.Load(), int32(100))
+}
+
+func TestParallelMapCollectErrors(t *testing.T) {
+	datas := []int{0, 1, 2, 3, 4, 5}
+	results, err := ParallelMap(nil, datas, 0, false, func(ctx context.Context, i int) (int, error) {
+		if i%2 == 1 {
+			return 0, fmt.Errorf("odd %d", i)
+		}
+		if i == 4 {
+			panic("four")
+		}
+		return i * i, nil
+	})
+
+	assert.Equal(t, []int{0, 0, 4, 0, 0, 0}, results)
+
+	errs, ok := err.(Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 4, errs.Len())
+	assert.Equal(t, "odd 1", errs[0].Error())
+	assert.Equal(t, "odd 3", errs[1].Error())
+
+	_, ok = errs[2].(*basic.PanicError)
+	assert.True(t, ok)
+}
+
+func TestParallelMapCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ParallelForEach(ctx, []int{1, 2, 3}, 1, false, func(ctx context.Context, i int) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParallelMapSeq(t *testing.T) {
+	var started atomic.Int32
+
+	seq := ParallelMapSeq(context.Background(), naturalSeq, 3, true, func(ctx context.Context, i int) (int, error) {
+		started.Add(1)
+		time.Sleep(time.Millisecond * time.Duration(i%3))
+		return i * 2, nil
+	})
+
+	var results []int
+	for result, err := range seq {
+		assert.Nil(t, err)
+		results = append(results, result)
+		if len(results) == 10 {
+			break
+		}
+	}
+
+	assert.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, results)
+	time.Sleep(time.Millisecond * 20)
+	assert.LessOrEqual(t, started.Load(), int32(14))
+}
+
+func TestParallelMapSeqJoinsProducer(t *testing.T) {
+	var returned, pulledAfterReturn, running atomic.Int32
+
+	source := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if returned.Load() != 0 {
+				pulledAfterReturn.Add(1)
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	seq := ParallelMapSeq(context.Background(), source, 4, true, func(ctx context.Context, i int) (int, error) {
+		running.Add(1)
+		defer running.Add(-1)
+		time.Sleep(time.Millisecond)
+		return i, nil
+	})
+
+	for i := range seq {
+		if i == 5 {
+			break
+		}
+	}
+	returned.Store(1)
+
+	assert.Equal(t, int32(0), running.Load())
+	time.Sleep(time.Millisecond * 20)
+	assert.Equal(t, int32(0), pulledAfterReturn.Load())
+}
+
+func TestParallelMapSeqStopWhenError(t *testing.T) {
+	seq := ParallelMapSeq(context.Background(), DataSeq(1, 2, 3, 4), 2, true, func(ctx context.Context, i int) (int, error) {
+		if i == 2 {
+			return 0, errors.New("two")
+		}
+		return i, nil
+	})
+
+	var results []int
+	var errs Errors
+	for result, err := range seq {
+		results = append(results, result)
+		errs = errs.Append(err)
+	}
+
+	assert.Equal(t, []int{1, 0}, results)
+	assert.EqualError(t, errs.Simplify(), "#0 two")
+}
@@ -1,9 +1,11 @@
 package stl
 
 import (
+	"context"
 	"errors"
 	"io"
 	"iter"
+	"time"
 )
 
 type Reader[Datas ~[]Data, Data any] interface {
@@ -185,11 +187,23 @@ func (r *PeekReader[Datas, Data]) Len() int {
 // ResumeReader 出错续读：底层 Read 返回非 nil 且非 io.EOF 时，若底层实现 closer 则先关闭，
 // 再以当前已成功交付给调用方的元素个数为 offset 调用 factory 创建新 Reader，并继续本次 Read（填满 p）。
 // offset 语义：从逻辑流起点算起，此前各次 Read 已累计返回给调用方的 Data 元素个数。
+//
+// 可选配置：WithBackoff 控制两次尝试之间的等待，WithRetryable 判断错误是否可重试，
+// WithCheckpointStore 持久化 offset，进程重启后从上次的进度续读。
 type ResumeReader[Datas ~[]Data, Data any] struct {
 	factory      func(offset int) (Reader[Datas, Data], error)
 	cur          Reader[Datas, Data]
 	read         int // 已累计成功 Read 出的元素个数，作为下次 factory 的 offset
 	triesPerRead int
+
+	ctx       context.Context
+	backoff   Backoff
+	retryable func(error) bool
+
+	store           CheckpointStore
+	checkpointEvery int
+	checkpointed    int
+	restored        bool
 }
 
 // NewResumeReader 使用 factory 延迟打开底层；factory(offset) 应返回从逻辑流第 offset 个元素起的 Reader。
@@ -200,6 +214,79 @@ func NewResumeReader[Datas ~[]Data, Data any](factory func(offset int) (Reader[D
 	return &ResumeReader[Datas, Data]{factory: factory, triesPerRead: triesPerRead}
 }
 
+// WithContext 设置等待重试时使用的 ctx，ctx 结束时 Read 返回 ctx.Err()
+func (rr *ResumeReader[Datas, Data]) WithContext(ctx context.Context) *ResumeReader[Datas, Data] {
+	rr.ctx = ctx
+	return rr
+}
+
+// WithBackoff 设置两次尝试之间的等待策略；未设置时立即重试，策略放弃时 Read 返回最后一次的错误
+func (rr *ResumeReader[Datas, Data]) WithBackoff(backoff Backoff) *ResumeReader[Datas, Data] {
+	rr.backoff = backoff
+	return rr
+}
+
+// WithRetryable 设置错误分类：返回 false 的错误不再重试，直接由 Read 返回。
+// 未设置时底层 Read 的错误均重试、factory 的错误均不重试；设置后两者都交由 retryable 判断。
+func (rr *ResumeReader[Datas, Data]) WithRetryable(retryable func(error) bool) *ResumeReader[Datas, Data] {
+	rr.retryable = retryable
+	return rr
+}
+
+// WithCheckpointStore 设置进度存储：首次打开前从 store 恢复 offset，
+// 此后每累计读出 every 个元素（every <= 0 时只在 EOF 与 Close 时）保存一次；也可调用 Checkpoint 手动保存
+func (rr *ResumeReader[Datas, Data]) WithCheckpointStore(store CheckpointStore, every int) *ResumeReader[Datas, Data] {
+	rr.store = store
+	rr.checkpointEvery = every
+	return rr
+}
+
+// Offset 返回已累计交付给调用方的元素个数（含从进度存储恢复的部分）
+func (rr *ResumeReader[Datas, Data]) Offset() int {
+	return rr.read
+}
+
+// Checkpoint 立即保存当前 offset，未设置进度存储时什么都不做
+func (rr *ResumeReader[Datas, Data]) Checkpoint() error {
+	if rr.store == nil {
+		return nil
+	}
+
+	if err := rr.store.SaveCheckpoint(rr.read); err != nil {
+		return err
+	}
+
+	rr.checkpointed = rr.read
+	return nil
+}
+
+// Close 关闭当前底层 Reader 并保存进度
+func (rr *ResumeReader[Datas, Data]) Close() error {
+	if rr.cur != nil {
+		Close(rr.cur)
+		rr.cur = nil
+	}
+	return rr.Checkpoint()
+}
+
+func (rr *ResumeReader[Datas, Data]) restore() error {
+	if rr.restored || rr.store == nil {
+		return nil
+	}
+
+	offset, ok, err := rr.store.LoadCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		rr.read, rr.checkpointed = offset, offset
+	}
+
+	rr.restored = true
+	return nil
+}
+
 func (rr *ResumeReader[Datas, Data]) openCurrent() error {
 	r, err := rr.factory(rr.read)
 	if err != nil {
@@ -215,16 +302,69 @@ func (rr *ResumeReader[Datas, Data]) openCurrent() error {
 	return nil
 }
 
-// Read 实现 Reader；底层非 EOF 错误时会关闭并换 reader 后重试，直到成功、EOF 或 factory 失败。
+func (rr *ResumeReader[Datas, Data]) isRetryable(err error, opening bool) bool {
+	if rr.retryable == nil {
+		return !opening
+	}
+	return rr.retryable(err)
+}
+
+// wait 按 backoff 等待第 attempt 次重试，放弃重试时返回 cause
+func (rr *ResumeReader[Datas, Data]) wait(attempt int, failedAt time.Time, cause error) error {
+	if rr.backoff == nil {
+		return nil
+	}
+
+	delay, ok := rr.backoff.NextDelay(attempt, time.Since(failedAt))
+	if !ok {
+		return cause
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	ctx := chanContext(rr.ctx)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Read 实现 Reader；底层非 EOF 错误时会关闭并换 reader 后重试，直到成功、EOF、错误不可重试或等待策略放弃。
+// 本次尝试次数用完时返回已读个数与 nil，下次 Read 会重新打开。
 func (rr *ResumeReader[Datas, Data]) Read(p []Data) (total int, err error) {
 	if len(p) == 0 {
 		return
 	}
 
-	for tries := rr.triesPerRead; tries > 0 && len(p) > 0; tries-- {
+	if err = rr.restore(); err != nil {
+		return
+	}
+
+	var failedAt time.Time
+	for attempt := 0; attempt < rr.triesPerRead && len(p) > 0; attempt++ {
+		if attempt > 0 {
+			if err = rr.wait(attempt, failedAt, err); err != nil {
+				return
+			}
+		}
+
 		if rr.cur == nil {
 			if err = rr.openCurrent(); err != nil {
-				return
+				if !rr.isRetryable(err, true) || attempt+1 == rr.triesPerRead {
+					return
+				}
+
+				if failedAt.IsZero() {
+					failedAt = time.Now()
+				}
+				continue
 			}
 		}
 
@@ -237,6 +377,9 @@ func (rr *ResumeReader[Datas, Data]) Read(p []Data) (total int, err error) {
 		}
 
 		if err == nil {
+			if rr.checkpointEvery > 0 && rr.read-rr.checkpointed >= rr.checkpointEvery {
+				err = rr.Checkpoint()
+			}
 			return
 		}
 
@@ -244,8 +387,19 @@ func (rr *ResumeReader[Datas, Data]) Read(p []Data) (total int, err error) {
 		rr.cur = nil
 
 		if err == io.EOF {
+			if cerr := rr.Checkpoint(); cerr != nil {
+				err = cerr
+			}
 			return
 		}
+
+		if !rr.isRetryable(err, false) {
+			return
+		}
+
+		if failedAt.IsZero() {
+			failedAt = time.Now()
+		}
 	}
 
 	return total, nil
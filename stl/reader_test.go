@@ -1,9 +1,13 @@
 package stl
 
 import (
+	"context"
 	"errors"
 	"io"
+	"math"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -339,3 +343,131 @@ func TestResumeReader_factoryNilReader(t *testing.T) {
 	_, err := rr.Read(make([]byte, 1))
 	require.Error(t, err)
 }
+
+func TestResumeReader_backoffRetriesFactory(t *testing.T) {
+	t.Parallel()
+	errOpen := errors.New("open failed")
+	var opens int
+	rr := NewResumeReader[[]byte, byte](func(offset int) (Reader[[]byte, byte], error) {
+		opens++
+		if opens < 3 {
+			return nil, errOpen
+		}
+		return &sliceReader{b: []byte("ok")}, nil
+	}, 5).
+		WithBackoff(NewConstantBackoff(time.Millisecond)).
+		WithRetryable(func(err error) bool { return errors.Is(err, errOpen) })
+
+	out, err := readAllByteReader(rr)
+	require.NoError(t, err)
+	require.Equal(t, []byte("ok"), out)
+	require.Equal(t, 3, opens)
+}
+
+func TestResumeReader_nonRetryable(t *testing.T) {
+	t.Parallel()
+	errFatal := errors.New("fatal")
+	var opens int
+	rr := NewResumeReader[[]byte, byte](func(offset int) (Reader[[]byte, byte], error) {
+		opens++
+		return &readOneThenErr{b: []byte("abc"), err: errFatal}, nil
+	}, 5).WithRetryable(func(err error) bool { return !errors.Is(err, errFatal) })
+
+	n, err := rr.Read(make([]byte, 3))
+	require.ErrorIs(t, err, errFatal)
+	require.Equal(t, 1, n)
+	require.Equal(t, 1, opens)
+}
+
+func TestResumeReader_backoffGiveUp(t *testing.T) {
+	t.Parallel()
+	errBoom := errors.New("boom")
+	var opens int
+	rr := NewResumeReader[[]byte, byte](func(offset int) (Reader[[]byte, byte], error) {
+		opens++
+		return &immediateErrCloser{err: errBoom, closed: new(int)}, nil
+	}, 100).WithBackoff(MaxElapsedBackoff(NewConstantBackoff(5*time.Millisecond), 20*time.Millisecond))
+
+	_, err := rr.Read(make([]byte, 1))
+	require.ErrorIs(t, err, errBoom)
+	require.Less(t, opens, 10)
+}
+
+func TestResumeReader_backoffContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rr := NewResumeReader[[]byte, byte](func(offset int) (Reader[[]byte, byte], error) {
+		return &immediateErrCloser{err: errors.New("boom"), closed: new(int)}, nil
+	}, 3).WithBackoff(NewConstantBackoff(time.Hour)).WithContext(ctx)
+
+	_, err := rr.Read(make([]byte, 1))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestResumeReader_checkpoint(t *testing.T) {
+	t.Parallel()
+	full := []byte("hello world")
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "offset"))
+	factory := func(offset int) (Reader[[]byte, byte], error) {
+		return &sliceReader{b: append([]byte(nil), full[offset:]...)}, nil
+	}
+
+	// 第一个进程读了 5 个后崩溃
+	rr := NewResumeReader[[]byte, byte](factory, 1).WithCheckpointStore(store, 5)
+	n, err := rr.Read(make([]byte, 5))
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+
+	offset, ok, err := store.LoadCheckpoint()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 5, offset)
+
+	// 第二个进程从进度续读
+	rr = NewResumeReader[[]byte, byte](factory, 1).WithCheckpointStore(store, 0)
+	out, err := readAllByteReader(rr)
+	require.NoError(t, err)
+	require.Equal(t, full[5:], out)
+	require.Equal(t, len(full), rr.Offset())
+
+	offset, _, err = store.LoadCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, len(full), offset)
+
+	require.NoError(t, store.Remove())
+	_, ok, err = store.LoadCheckpoint()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+	backoff := NewExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	for attempt, expected := range []time.Duration{10, 20, 40, 50, 50} {
+		delay, ok := backoff.NextDelay(attempt+1, 0)
+		require.True(t, ok)
+		require.Equal(t, expected*time.Millisecond, delay)
+	}
+
+	backoff.WithJitter(0.5)
+	for range 100 {
+		delay, _ := backoff.NextDelay(2, 0)
+		require.GreaterOrEqual(t, delay, 10*time.Millisecond)
+		require.LessOrEqual(t, delay, 30*time.Millisecond)
+	}
+
+	unlimited := NewExponentialBackoff(time.Second, 0).WithJitter(0.5)
+	for _, attempt := range []int{64, 100, 2000} {
+		delay, ok := unlimited.NextDelay(attempt, 0)
+		require.True(t, ok)
+		require.Equal(t, time.Duration(math.MaxInt64), delay)
+	}
+
+	limited := MaxElapsedBackoff(NewConstantBackoff(time.Second), 100*time.Millisecond)
+	delay, ok := limited.NextDelay(1, 40*time.Millisecond)
+	require.True(t, ok)
+	require.Equal(t, 60*time.Millisecond, delay)
+	_, ok = limited.NextDelay(2, 100*time.Millisecond)
+	require.False(t, ok)
+}
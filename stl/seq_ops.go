@@ -0,0 +1,404 @@
+package stl
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// 本文件为 iter.Seq / iter.Seq2 的惰性组合子：仅在被遍历时才拉取上游数据，
+// 可与 ReadSeq、WriteSeq、SeqFromReader 等自由组合。
+
+func FilterSeq[Data any](seq iter.Seq[Data], test func(Data) bool) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		for data := range seq {
+			if test(data) && !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+func TakeSeq[Data any](seq iter.Seq[Data], n int) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		if n <= 0 {
+			return
+		}
+
+		taken := 0
+		for data := range seq {
+			if !yield(data) {
+				return
+			}
+
+			taken++
+			if taken >= n {
+				return
+			}
+		}
+	}
+}
+
+func SkipSeq[Data any](seq iter.Seq[Data], n int) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		skipped := 0
+		for data := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+func TakeWhileSeq[Data any](seq iter.Seq[Data], test func(Data) bool) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		for data := range seq {
+			if !test(data) || !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+func SkipWhileSeq[Data any](seq iter.Seq[Data], test func(Data) bool) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		skipping := true
+		for data := range seq {
+			if skipping && test(data) {
+				continue
+			}
+
+			skipping = false
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq 按 size 个一组切分，最后一组可能不足 size；每组均为新分配的切片。
+func ChunkSeq[Data any](seq iter.Seq[Data], size int) iter.Seq[[]Data] {
+	return func(yield func([]Data) bool) {
+		if size <= 0 {
+			return
+		}
+
+		chunk := make([]Data, 0, size)
+		for data := range seq {
+			chunk = append(chunk, data)
+			if len(chunk) < size {
+				continue
+			}
+
+			if !yield(chunk) {
+				return
+			}
+
+			chunk = make([]Data, 0, size)
+		}
+
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// WindowSeq 产出长度为 size 的滑动窗口，元素不足 size 时不产出；每个窗口均为新分配的切片。
+func WindowSeq[Data any](seq iter.Seq[Data], size int) iter.Seq[[]Data] {
+	return func(yield func([]Data) bool) {
+		if size <= 0 {
+			return
+		}
+
+		window := make([]Data, 0, size)
+		for data := range seq {
+			if len(window) == size {
+				window = window[1:]
+			}
+
+			window = append(window, data)
+			if len(window) < size {
+				continue
+			}
+
+			if !yield(DupSlice(window)) {
+				return
+			}
+		}
+	}
+}
+
+// ZipSeq 将两个序列按位置配对，任一序列结束即结束。
+func ZipSeq[A any, B any](as iter.Seq[A], bs iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(bs)
+		defer stop()
+
+		for a := range as {
+			b, ok := nextB()
+			if !ok || !yield(a, b) {
+				return
+			}
+		}
+	}
+}
+
+func FlatMapSeq[Data any, Result any](seq iter.Seq[Data], mapper func(Data) iter.Seq[Result]) iter.Seq[Result] {
+	return func(yield func(Result) bool) {
+		for data := range seq {
+			for result := range mapper(data) {
+				if !yield(result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func DistinctSeq[Data comparable](seq iter.Seq[Data]) iter.Seq[Data] {
+	return DistinctBySeq(seq, Echo[Data])
+}
+
+// DistinctBySeq 按 key 去重，保留首次出现的元素；已见过的 key 会一直保存在内存中。
+func DistinctBySeq[Data any, Key comparable](seq iter.Seq[Data], key func(Data) Key) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		seen := NewSet[Key]()
+		for data := range seq {
+			k := key(data)
+			if seen.Contain(k) {
+				continue
+			}
+
+			seen.Add(k)
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+// GroupBySeq 将 key 相同的连续元素归为一组（与 Python itertools.groupby 一致），
+// 需要全局分组时请先按 key 排序，或直接使用 ReadSeq 后按 map 归类。
+func GroupBySeq[Data any, Key comparable](seq iter.Seq[Data], key func(Data) Key) iter.Seq2[Key, []Data] {
+	return func(yield func(Key, []Data) bool) {
+		var current Key
+		var group []Data
+
+		for data := range seq {
+			k := key(data)
+			if len(group) > 0 && k != current {
+				if !yield(current, group) {
+					return
+				}
+				group = nil
+			}
+
+			current = k
+			group = append(group, data)
+		}
+
+		if len(group) > 0 {
+			yield(current, group)
+		}
+	}
+}
+
+// ScanSeq 依次产出每一步的累积结果（不含 initial 本身）。
+func ScanSeq[Result any, Data any](seq iter.Seq[Data], reducer func(Result, Data) Result, initial Result) iter.Seq[Result] {
+	return func(yield func(Result) bool) {
+		result := initial
+		for data := range seq {
+			result = reducer(result, data)
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+func EnumerateSeq[Data any](seq iter.Seq[Data]) iter.Seq2[int, Data] {
+	return func(yield func(int, Data) bool) {
+		i := 0
+		for data := range seq {
+			if !yield(i, data) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// InterleaveSeq 轮流从各序列各取一个元素，已结束的序列跳过，直到全部结束。
+func InterleaveSeq[Data any](seqs ...iter.Seq[Data]) iter.Seq[Data] {
+	return func(yield func(Data) bool) {
+		nexts := make([]func() (Data, bool), 0, len(seqs))
+		for _, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts = append(nexts, next)
+		}
+
+		for len(nexts) > 0 {
+			alives := nexts[:0]
+			for _, next := range nexts {
+				data, ok := next()
+				if !ok {
+					continue
+				}
+
+				if !yield(data) {
+					return
+				}
+
+				alives = append(alives, next)
+			}
+			nexts = alives
+		}
+	}
+}
+
+func ReduceSeq[Result any, Data any](seq iter.Seq[Data], reducer func(Result, Data) Result, initial Result) (result Result) {
+	result = initial
+	for data := range seq {
+		result = reducer(result, data)
+	}
+	return
+}
+
+func CountSeq[Data any](seq iter.Seq[Data]) (n int) {
+	for range seq {
+		n++
+	}
+	return
+}
+
+func FirstSeq[Data any](seq iter.Seq[Data]) (first Data, ok bool) {
+	for data := range seq {
+		return data, true
+	}
+	return
+}
+
+func MinSeq[Data constraints.Ordered](seq iter.Seq[Data]) (result Data, ok bool) {
+	return MinSeqBy(seq, Less[Data])
+}
+
+func MaxSeq[Data constraints.Ordered](seq iter.Seq[Data]) (result Data, ok bool) {
+	return MinSeqBy(seq, Greater[Data])
+}
+
+// MinSeqBy 返回按 less 排序最小的元素，相等时取先出现的；序列为空时 ok 为 false。
+func MinSeqBy[Data any](seq iter.Seq[Data], less func(a, b Data) bool) (result Data, ok bool) {
+	for data := range seq {
+		if !ok || less(data, result) {
+			result = data
+			ok = true
+		}
+	}
+	return
+}
+
+func FilterSeq2[K any, V any](seq iter.Seq2[K, V], test func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if test(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func MapSeq2[DstK any, DstV any, SrcK any, SrcV any](seq iter.Seq2[SrcK, SrcV], mapper func(SrcK, SrcV) (DstK, DstV)) iter.Seq2[DstK, DstV] {
+	return func(yield func(DstK, DstV) bool) {
+		for k, v := range seq {
+			if !yield(mapper(k, v)) {
+				return
+			}
+		}
+	}
+}
+
+func TakeSeq2[K any, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n <= 0 {
+			return
+		}
+
+		taken := 0
+		for k, v := range seq {
+			if !yield(k, v) {
+				return
+			}
+
+			taken++
+			if taken >= n {
+				return
+			}
+		}
+	}
+}
+
+func SkipSeq2[K any, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		skipped := 0
+		for k, v := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func TakeWhileSeq2[K any, V any](seq iter.Seq2[K, V], test func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if !test(k, v) || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func KeySeqOf[K any, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return Seq2ToSeq(seq, func(k K, _ V) K {
+		return k
+	})
+}
+
+func ValueSeqOf[K any, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return Seq2ToSeq(seq, func(_ K, v V) V {
+		return v
+	})
+}
+
+func ReduceSeq2[Result any, K any, V any](seq iter.Seq2[K, V], reducer func(Result, K, V) Result, initial Result) (result Result) {
+	result = initial
+	for k, v := range seq {
+		result = reducer(result, k, v)
+	}
+	return
+}
+
+func CountSeq2[K any, V any](seq iter.Seq2[K, V]) (n int) {
+	for range seq {
+		n++
+	}
+	return
+}
+
+func FirstSeq2[K any, V any](seq iter.Seq2[K, V]) (k K, v V, ok bool) {
+	for k, v := range seq {
+		return k, v, true
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package stl
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func naturalSeq(yield func(int) bool) {
+	for i := 0; ; i++ {
+		if !yield(i) {
+			return
+		}
+	}
+}
+
+func collectSeq2[K any, V any](seq iter.Seq2[K, V]) KeyValuePairs[K, V] {
+	var pairs KeyValuePairs[K, V]
+	for k, v := range seq {
+		pairs = append(pairs, KeyValuePair[K, V]{Key: k, Value: v})
+	}
+	return pairs
+}
+
+func TestFilterTakeSkip(t *testing.T) {
+	even := FilterSeq(naturalSeq, func(i int) bool { return i%2 == 0 })
+	assert.Equal(t, []int{0, 2, 4, 6}, ReadSeq[[]int](TakeSeq(even, 4)))
+	assert.Equal(t, []int{6, 8}, ReadSeq[[]int](TakeSeq(SkipSeq(even, 3), 2)))
+	assert.Nil(t, ReadSeq[[]int](TakeSeq(even, 0)))
+
+	lessThan := func(n int) func(int) bool {
+		return func(i int) bool { return i < n }
+	}
+	assert.Equal(t, []int{0, 1, 2}, ReadSeq[[]int](TakeWhileSeq(naturalSeq, lessThan(3))))
+	assert.Equal(t, []int{3, 4}, ReadSeq[[]int](TakeSeq(SkipWhileSeq(naturalSeq, lessThan(3)), 2)))
+}
+
+func TestChunkWindow(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, ReadSeq[[][]int](ChunkSeq(DataSeq(1, 2, 3, 4, 5), 2)))
+	assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}}, ReadSeq[[][]int](WindowSeq(DataSeq(1, 2, 3, 4), 3)))
+	assert.Nil(t, ReadSeq[[][]int](WindowSeq(DataSeq(1, 2), 3)))
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}}, ReadSeq[[][]int](TakeSeq(ChunkSeq(naturalSeq, 2), 2)))
+}
+
+func TestZipEnumerateInterleave(t *testing.T) {
+	zipped := collectSeq2(ZipSeq(DataSeq("a", "b", "c"), naturalSeq))
+	assert.Equal(t, KeyValuePairs[string, int]{{"a", 0}, {"b", 1}, {"c", 2}}, zipped)
+
+	enumerated := collectSeq2(EnumerateSeq(DataSeq("x", "y")))
+	assert.Equal(t, KeyValuePairs[int, string]{{0, "x"}, {1, "y"}}, enumerated)
+
+	interleaved := InterleaveSeq(DataSeq(1, 4), DataSeq(2, 5, 7), DataSeq(3))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 7}, ReadSeq[[]int](interleaved))
+	assert.Equal(t, []int{1, 2}, ReadSeq[[]int](TakeSeq(interleaved, 2)))
+}
+
+func TestFlatMapDistinctGroup(t *testing.T) {
+	repeated := FlatMapSeq(DataSeq(1, 2, 3), func(i int) iter.Seq[int] {
+		return TakeSeq(func(yield func(int) bool) {
+			for yield(i) {
+			}
+		}, i)
+	})
+	assert.Equal(t, []int{1, 2, 2, 3, 3, 3}, ReadSeq[[]int](repeated))
+	assert.Equal(t, []int{1, 2, 3}, ReadSeq[[]int](DistinctSeq(repeated)))
+
+	words := DataSeq("apple", "avocado", "banana", "blueberry", "cherry", "apricot")
+	assert.Equal(t, []string{"apple", "banana", "cherry"}, ReadSeq[[]string](DistinctBySeq(words, func(s string) byte { return s[0] })))
+
+	groups := collectSeq2(GroupBySeq(words, func(s string) byte { return s[0] }))
+	assert.Equal(t, KeyValuePairs[byte, []string]{
+		{'a', []string{"apple", "avocado"}},
+		{'b', []string{"banana", "blueberry"}},
+		{'c', []string{"cherry"}},
+		{'a', []string{"apricot"}},
+	}, groups)
+}
+
+func TestScanAndTerminals(t *testing.T) {
+	sum := func(a, b int) int { return a + b }
+	assert.Equal(t, []int{1, 3, 6, 10}, ReadSeq[[]int](ScanSeq(DataSeq(1, 2, 3, 4), sum, 0)))
+	assert.Equal(t, 10, ReduceSeq(DataSeq(1, 2, 3, 4), sum, 0))
+	assert.Equal(t, 4, CountSeq(DataSeq(1, 2, 3, 4)))
+
+	first, ok := FirstSeq(SkipSeq(naturalSeq, 5))
+	assert.True(t, ok)
+	assert.Equal(t, 5, first)
+
+	_, ok = FirstSeq(EmptySeq[int])
+	assert.False(t, ok)
+
+	min, ok := MinSeq(DataSeq(3, 1, 2))
+	assert.True(t, ok)
+	assert.Equal(t, 1, min)
+
+	max, ok := MaxSeq(DataSeq(3, 1, 2))
+	assert.True(t, ok)
+	assert.Equal(t, 3, max)
+
+	_, ok = MaxSeq(EmptySeq[int])
+	assert.False(t, ok)
+}
+
+func TestSeq2Ops(t *testing.T) {
+	seq := IndexDataSeq("a", "b", "c", "d")
+
+	odd := FilterSeq2(seq, func(i int, _ string) bool { return i%2 == 1 })
+	assert.Equal(t, []string{"b", "d"}, ReadSeq[[]string](ValueSeqOf(odd)))
+	assert.Equal(t, []int{1, 2}, ReadSeq[[]int](KeySeqOf(TakeSeq2(SkipSeq2(seq, 1), 2))))
+	assert.Equal(t, 2, CountSeq2(TakeWhileSeq2(seq, func(i int, _ string) bool { return i < 2 })))
+
+	swapped := MapSeq2(seq, func(i int, s string) (string, int) { return s, i })
+	k, v, ok := FirstSeq2(swapped)
+	assert.True(t, ok)
+	assert.Equal(t, "a", k)
+	assert.Equal(t, 0, v)
+
+	joined := ReduceSeq2(seq, func(result string, _ int, s string) string { return result + s }, "")
+	assert.Equal(t, "abcd", joined)
+}
+
+func TestSeqOpsWithReader(t *testing.T) {
+	reader := NewBufferFrom(ReadSeq[[]int](TakeSeq(naturalSeq, 20)))
+	chunks := ChunkSeq(FilterSeq(SeqFromReader[[]int](reader), func(i int) bool { return i%3 == 0 }), 2)
+	assert.Equal(t, [][]int{{0, 3}, {6, 9}}, ReadSeq[[][]int](TakeSeq(chunks, 2)))
+}
@@ -0,0 +1,406 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+var (
+	ErrSketchMismatch  = errors.New("stl: sketch parameters mismatch")
+	ErrSketchCorrupted = errors.New("stl: corrupted sketch data")
+)
+
+// Hasher 将键映射为 64 位哈希值。需要合并或序列化的实例须使用相同的 Hasher。
+type Hasher[Key comparable] func(key Key) uint64
+
+// DefaultHasher 基于 FNV-1a，结果与进程无关，可用于跨进程合并。
+// 字符串、整数、浮点数、布尔值按值哈希，其他类型按 %#v 的输出哈希。
+func DefaultHasher[Key comparable](key Key) uint64 {
+	h := fnv.New64a()
+
+	var buf [8]byte
+	switch k := any(key).(type) {
+	case string:
+		h.Write([]byte(k))
+		return mixHash(h.Sum64())
+	case int:
+		binary.BigEndian.PutUint64(buf[:], uint64(k))
+		h.Write(buf[:])
+		return mixHash(h.Sum64())
+	}
+
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.String:
+		h.Write([]byte(value.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.BigEndian.PutUint64(buf[:], uint64(value.Int()))
+		h.Write(buf[:])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.BigEndian.PutUint64(buf[:], value.Uint())
+		h.Write(buf[:])
+	case reflect.Float32, reflect.Float64:
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(value.Float()))
+		h.Write(buf[:])
+	case reflect.Bool:
+		if value.Bool() {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+	default:
+		fmt.Fprintf(h, "%#v", key)
+	}
+
+	return mixHash(h.Sum64())
+}
+
+// mixHash 为 splitmix64 的末轮混淆，改善低位分布
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hashPair 由一个哈希值派生两个哈希值，用于双重哈希：h1 + i*h2
+func hashPair(h uint64) (uint64, uint64) {
+	return h, mixHash(h^0x9e3779b97f4a7c15) | 1
+}
+
+// sketchReader 按序读取序列化数据，出错后的读取均返回零值
+type sketchReader struct {
+	data []byte
+	err  error
+}
+
+func newSketchReader(data []byte, magic string) *sketchReader {
+	reader := &sketchReader{data: data}
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		reader.err = ErrSketchCorrupted
+		return reader
+	}
+
+	reader.data = data[len(magic):]
+	return reader
+}
+
+func (reader *sketchReader) uvarint() uint64 {
+	if reader.err != nil {
+		return 0
+	}
+
+	value, n := binary.Uvarint(reader.data)
+	if n <= 0 {
+		reader.err = ErrSketchCorrupted
+		return 0
+	}
+
+	reader.data = reader.data[n:]
+	return value
+}
+
+func (reader *sketchReader) bytes(n uint64) []byte {
+	if reader.err != nil {
+		return nil
+	}
+
+	if uint64(len(reader.data)) < n {
+		reader.err = ErrSketchCorrupted
+		return nil
+	}
+
+	data := reader.data[:n]
+	reader.data = reader.data[n:]
+	return data
+}
+
+func (reader *sketchReader) finish() error {
+	if reader.err == nil && len(reader.data) > 0 {
+		reader.err = ErrSketchCorrupted
+	}
+	return reader.err
+}
+
+// CountMinSketch 以固定内存估计各键的计数，估计值不小于真实值。
+type CountMinSketch[Key comparable] struct {
+	width    uint64
+	depth    uint64
+	total    uint64
+	counters []uint64
+	hasher   Hasher[Key]
+}
+
+// NewCountMinSketch 创建 depth 行、每行 width 个计数器的 sketch
+func NewCountMinSketch[Key comparable](width, depth int) *CountMinSketch[Key] {
+	width = max(width, 1)
+	depth = max(depth, 1)
+	return &CountMinSketch[Key]{
+		width:    uint64(width),
+		depth:    uint64(depth),
+		counters: make([]uint64, width*depth),
+		hasher:   DefaultHasher[Key],
+	}
+}
+
+// NewCountMinSketchWithEstimates 按误差要求创建：以 1-delta 的概率，估计值超出真实值不多于 epsilon*总数
+func NewCountMinSketchWithEstimates[Key comparable](epsilon, delta float64) *CountMinSketch[Key] {
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return NewCountMinSketch[Key](width, depth)
+}
+
+func (sketch *CountMinSketch[Key]) WithHasher(hasher Hasher[Key]) *CountMinSketch[Key] {
+	sketch.hasher = hasher
+	return sketch
+}
+
+func (sketch *CountMinSketch[Key]) hash(key Key) (uint64, uint64) {
+	if sketch.hasher == nil {
+		sketch.hasher = DefaultHasher[Key]
+	}
+	return hashPair(sketch.hasher(key))
+}
+
+// Add 累加 key 的计数，返回累加后的估计值
+func (sketch *CountMinSketch[Key]) Add(key Key, count uint64) uint64 {
+	h1, h2 := sketch.hash(key)
+
+	estimate := uint64(math.MaxUint64)
+	for i := uint64(0); i < sketch.depth; i++ {
+		index := i*sketch.width + (h1+i*h2)%sketch.width
+		sketch.counters[index] += count
+		estimate = min(estimate, sketch.counters[index])
+	}
+
+	sketch.total += count
+	return estimate
+}
+
+func (sketch *CountMinSketch[Key]) Estimate(key Key) uint64 {
+	h1, h2 := sketch.hash(key)
+
+	estimate := uint64(math.MaxUint64)
+	for i := uint64(0); i < sketch.depth; i++ {
+		estimate = min(estimate, sketch.counters[i*sketch.width+(h1+i*h2)%sketch.width])
+	}
+	return estimate
+}
+
+// Total 返回累加的总数
+func (sketch *CountMinSketch[Key]) Total() uint64 {
+	return sketch.total
+}
+
+func (sketch *CountMinSketch[Key]) Width() int {
+	return int(sketch.width)
+}
+
+func (sketch *CountMinSketch[Key]) Depth() int {
+	return int(sketch.depth)
+}
+
+// Merge 合并另一个同尺寸的 sketch，尺寸不同时返回 ErrSketchMismatch
+func (sketch *CountMinSketch[Key]) Merge(other *CountMinSketch[Key]) error {
+	if sketch.width != other.width || sketch.depth != other.depth {
+		return ErrSketchMismatch
+	}
+
+	for i, count := range other.counters {
+		sketch.counters[i] += count
+	}
+	sketch.total += other.total
+
+	return nil
+}
+
+func (sketch *CountMinSketch[Key]) Reset() {
+	clear(sketch.counters)
+	sketch.total = 0
+}
+
+const countMinSketchMagic = "cms\x01"
+
+func (sketch *CountMinSketch[Key]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(countMinSketchMagic)+len(sketch.counters)*2+16)
+	data = append(data, countMinSketchMagic...)
+	data = binary.AppendUvarint(data, sketch.width)
+	data = binary.AppendUvarint(data, sketch.depth)
+	data = binary.AppendUvarint(data, sketch.total)
+	for _, count := range sketch.counters {
+		data = binary.AppendUvarint(data, count)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 恢复尺寸与计数，Hasher 保持不变（未设置时为 DefaultHasher）
+func (sketch *CountMinSketch[Key]) UnmarshalBinary(data []byte) error {
+	reader := newSketchReader(data, countMinSketchMagic)
+
+	width := reader.uvarint()
+	depth := reader.uvarint()
+	total := reader.uvarint()
+	// 每个计数至少占 1 字节；用除法比较以免 width*depth 溢出
+	if reader.err == nil && (width == 0 || depth == 0 || width > uint64(len(reader.data))/depth) {
+		return ErrSketchCorrupted
+	}
+
+	counters := make([]uint64, width*depth)
+	for i := range counters {
+		counters[i] = reader.uvarint()
+	}
+
+	if err := reader.finish(); err != nil {
+		return err
+	}
+
+	sketch.width, sketch.depth, sketch.total, sketch.counters = width, depth, total, counters
+	return nil
+}
+
+// TopK 基于 CountMinSketch 跟踪计数最大的 k 个键（heavy hitters）
+type TopK[Key comparable] struct {
+	k          int
+	sketch     *CountMinSketch[Key]
+	heap       *IndexedHeap[KeyValuePair[Key, uint64]]
+	candidates map[Key]*IndexedHeapHandle[KeyValuePair[Key, uint64]]
+}
+
+// NewTopK 使用给定的 sketch 统计计数，sketch 为 nil 时按 epsilon=0.001、delta=0.01 创建
+func NewTopK[Key comparable](k int, sketch *CountMinSketch[Key]) *TopK[Key] {
+	k = max(k, 1)
+	if sketch == nil {
+		sketch = NewCountMinSketchWithEstimates[Key](0.001, 0.01)
+	}
+
+	return &TopK[Key]{
+		k:      k,
+		sketch: sketch,
+		heap: NewIndexedHeap(func(a, b KeyValuePair[Key, uint64]) bool {
+			return a.Value < b.Value
+		}, k),
+		candidates: make(map[Key]*IndexedHeapHandle[KeyValuePair[Key, uint64]], k),
+	}
+}
+
+func (topk *TopK[Key]) Sketch() *CountMinSketch[Key] {
+	return topk.sketch
+}
+
+// Add 累加 key 的计数，返回估计值
+func (topk *TopK[Key]) Add(key Key, count uint64) uint64 {
+	estimate := topk.sketch.Add(key, count)
+	topk.offer(key, estimate)
+	return estimate
+}
+
+func (topk *TopK[Key]) offer(key Key, estimate uint64) {
+	pair := KeyValuePair[Key, uint64]{Key: key, Value: estimate}
+
+	if handle, ok := topk.candidates[key]; ok {
+		topk.heap.Update(handle, pair)
+		return
+	}
+
+	if topk.heap.Len() >= topk.k {
+		if topk.heap.Peek().Value >= estimate {
+			return
+		}
+		delete(topk.candidates, topk.heap.Pop().Key)
+	}
+
+	topk.candidates[key] = topk.heap.PushHandle(pair)
+}
+
+// List 按估计值从大到小返回当前的 k 个键
+func (topk *TopK[Key]) List() KeyValuePairs[Key, uint64] {
+	pairs := make(KeyValuePairs[Key, uint64], 0, len(topk.candidates))
+	for _, handle := range topk.candidates {
+		pairs = append(pairs, handle.Data())
+	}
+
+	return pairs.Sort(func(a, b KeyValuePair[Key, uint64]) bool {
+		return a.Value > b.Value
+	})
+}
+
+// Merge 合并另一个 TopK：合并 sketch 后按新的估计值重新挑选候选键
+func (topk *TopK[Key]) Merge(other *TopK[Key]) error {
+	if err := topk.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+
+	keys := MapKeys(topk.candidates)
+	for key := range other.candidates {
+		if _, ok := topk.candidates[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	topk.heap.Clear()
+	clear(topk.candidates)
+	for _, key := range keys {
+		topk.offer(key, topk.sketch.Estimate(key))
+	}
+
+	return nil
+}
+
+const topKMagic = "tpk\x01"
+
+// MarshalBinary 序列化 k、sketch 与候选键，候选键使用 gob 编码
+func (topk *TopK[Key]) MarshalBinary() ([]byte, error) {
+	sketch, err := topk.sketch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys bytes.Buffer
+	if err := gob.NewEncoder(&keys).Encode(MapKeys(topk.candidates)); err != nil {
+		return nil, err
+	}
+
+	data := append([]byte(topKMagic), binary.AppendUvarint(nil, uint64(topk.k))...)
+	data = binary.AppendUvarint(data, uint64(len(sketch)))
+	data = append(data, sketch...)
+	data = append(data, keys.Bytes()...)
+	return data, nil
+}
+
+func (topk *TopK[Key]) UnmarshalBinary(data []byte) error {
+	reader := newSketchReader(data, topKMagic)
+
+	k := reader.uvarint()
+	sketchData := reader.bytes(reader.uvarint())
+	if reader.err != nil || k == 0 {
+		return ErrSketchCorrupted
+	}
+
+	sketch := NewCountMinSketch[Key](1, 1)
+	if topk.sketch != nil {
+		sketch.hasher = topk.sketch.hasher
+	}
+	if err := sketch.UnmarshalBinary(sketchData); err != nil {
+		return err
+	}
+
+	var keys []Key
+	if err := gob.NewDecoder(bytes.NewReader(reader.data)).Decode(&keys); err != nil {
+		return fmt.Errorf("%w: %w", ErrSketchCorrupted, err)
+	}
+
+	*topk = *NewTopK(int(k), sketch)
+	for _, key := range keys {
+		topk.offer(key, sketch.Estimate(key))
+	}
+
+	return nil
+}
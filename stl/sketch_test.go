@@ -0,0 +1,110 @@
+package stl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHasher(t *testing.T) {
+	type Name string
+	type Point struct{ X, Y int }
+
+	assert.Equal(t, DefaultHasher("a"), DefaultHasher(Name("a")))
+	assert.Equal(t, DefaultHasher(1), DefaultHasher(int64(1)))
+	assert.NotEqual(t, DefaultHasher("a"), DefaultHasher("b"))
+	assert.NotEqual(t, DefaultHasher(Point{1, 2}), DefaultHasher(Point{2, 1}))
+	assert.Equal(t, DefaultHasher(Point{1, 2}), DefaultHasher(Point{1, 2}))
+}
+
+func TestCountMinSketch(t *testing.T) {
+	sketch := NewCountMinSketchWithEstimates[string](0.01, 0.01)
+	assert.Equal(t, 272, sketch.Width())
+	assert.Equal(t, 5, sketch.Depth())
+
+	for i := 0; i < 1000; i++ {
+		sketch.Add(fmt.Sprint("key", i%100), 1)
+	}
+	assert.Equal(t, uint64(42), sketch.Add("hot", 42))
+	assert.Equal(t, uint64(1042), sketch.Total())
+
+	for i := 0; i < 100; i++ {
+		estimate := sketch.Estimate(fmt.Sprint("key", i))
+		assert.GreaterOrEqual(t, estimate, uint64(10))
+		assert.LessOrEqual(t, estimate, uint64(10+21))
+	}
+
+	other := NewCountMinSketchWithEstimates[string](0.01, 0.01)
+	other.Add("hot", 8)
+	assert.Nil(t, sketch.Merge(other))
+	assert.GreaterOrEqual(t, sketch.Estimate("hot"), uint64(50))
+	assert.Equal(t, ErrSketchMismatch, sketch.Merge(NewCountMinSketch[string](10, 5)))
+
+	data, err := sketch.MarshalBinary()
+	assert.Nil(t, err)
+
+	var restored CountMinSketch[string]
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, sketch.Estimate("hot"), restored.Estimate("hot"))
+	assert.Equal(t, sketch.Total(), restored.Total())
+
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(data[:len(data)-1]))
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary([]byte("xyz")))
+
+	// width*depth 溢出为 0
+	crafted := []byte(countMinSketchMagic)
+	crafted = binary.AppendUvarint(crafted, 1<<32)
+	crafted = binary.AppendUvarint(crafted, 1<<32)
+	crafted = binary.AppendUvarint(crafted, 0)
+	assert.Equal(t, ErrSketchCorrupted, restored.UnmarshalBinary(crafted))
+	restored.Add("hot", 1)
+
+	sketch.Reset()
+	assert.Equal(t, uint64(0), sketch.Estimate("hot"))
+}
+
+func TestTopK(t *testing.T) {
+	shards := []*TopK[string]{NewTopK[string](3, nil), NewTopK[string](3, nil)}
+	for i := 0; i < 2000; i++ {
+		shard := shards[i%2]
+		switch {
+		case i%10 == 0:
+			shard.Add("a", 2)
+		case i%10 < 3:
+			shard.Add("b", 3)
+		case i%10 == 3:
+			shard.Add("c", 1)
+		default:
+			shard.Add(fmt.Sprint("noise", i), 1)
+		}
+	}
+
+	assert.Nil(t, shards[0].Merge(shards[1]))
+
+	top := shards[0].List()
+	assert.Equal(t, []string{"b", "a", "c"}, ReadSeq[[]string](top.KeySeq()))
+	assert.GreaterOrEqual(t, top[0].Value, uint64(1200))
+
+	data, err := shards[0].MarshalBinary()
+	assert.Nil(t, err)
+
+	var restored TopK[string]
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, top, restored.List())
+
+	restored.Add("d", 10000)
+	assert.Equal(t, []string{"d", "b", "a"}, ReadSeq[[]string](restored.List().KeySeq()))
+
+	assert.NotNil(t, restored.UnmarshalBinary(data[:len(data)-3]))
+}
+
+func TestTopKNonPositiveK(t *testing.T) {
+	for _, k := range []int{-1, 0} {
+		topk := NewTopK[string](k, nil)
+		topk.Add("a", 1)
+		topk.Add("b", 2)
+		assert.Equal(t, []string{"b"}, ReadSeq[[]string](topk.List().KeySeq()))
+	}
+}
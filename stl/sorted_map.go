@@ -0,0 +1,451 @@
+package stl
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+type sortedMapNode[Key any, Value any] struct {
+	key    Key
+	value  Value
+	left   *sortedMapNode[Key, Value]
+	right  *sortedMapNode[Key, Value]
+	height int
+	size   int
+}
+
+func (n *sortedMapNode[Key, Value]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *sortedMapNode[Key, Value]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *sortedMapNode[Key, Value]) update() *sortedMapNode[Key, Value] {
+	n.height = max(n.left.getHeight(), n.right.getHeight()) + 1
+	n.size = n.left.getSize() + n.right.getSize() + 1
+	return n
+}
+
+func (n *sortedMapNode[Key, Value]) rotateLeft() *sortedMapNode[Key, Value] {
+	right := n.right
+	n.right = right.left
+	right.left = n.update()
+	return right.update()
+}
+
+func (n *sortedMapNode[Key, Value]) rotateRight() *sortedMapNode[Key, Value] {
+	left := n.left
+	n.left = left.right
+	left.right = n.update()
+	return left.update()
+}
+
+func (n *sortedMapNode[Key, Value]) balance() *sortedMapNode[Key, Value] {
+	n.update()
+
+	switch factor := n.left.getHeight() - n.right.getHeight(); {
+	case factor > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case factor < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+
+	return n
+}
+
+// SortedMap 是按键有序的映射（AVL 树），插入、删除、查找、排名均为 O(log n)。
+// 零值不可用，须通过 NewSortedMap 或 NewSortedMapBy 创建；非并发安全，遍历过程中不能修改。
+type SortedMap[Key any, Value any] struct {
+	root *sortedMapNode[Key, Value]
+	less func(a, b Key) bool
+}
+
+func NewSortedMap[Key constraints.Ordered, Value any]() *SortedMap[Key, Value] {
+	return NewSortedMapBy[Key, Value](Less[Key])
+}
+
+// NewSortedMapBy 使用自定义比较函数，less(a, b) 与 less(b, a) 均为 false 的键视为同一个键
+func NewSortedMapBy[Key any, Value any](less func(a, b Key) bool) *SortedMap[Key, Value] {
+	return &SortedMap[Key, Value]{
+		less: less,
+	}
+}
+
+func (m *SortedMap[Key, Value]) Len() int {
+	return m.root.getSize()
+}
+
+func (m *SortedMap[Key, Value]) Clear() {
+	m.root = nil
+}
+
+func (m *SortedMap[Key, Value]) find(key Key) *sortedMapNode[Key, Value] {
+	for n := m.root; n != nil; {
+		switch {
+		case m.less(key, n.key):
+			n = n.left
+		case m.less(n.key, key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+func (m *SortedMap[Key, Value]) Load(key Key) (value Value, ok bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+	return
+}
+
+func (m *SortedMap[Key, Value]) Get(key Key) Value {
+	value, _ := m.Load(key)
+	return value
+}
+
+func (m *SortedMap[Key, Value]) Contains(key Key) bool {
+	return m.find(key) != nil
+}
+
+func (m *SortedMap[Key, Value]) Store(key Key, value Value) {
+	m.Swap(key, value)
+}
+
+func (m *SortedMap[Key, Value]) Swap(key Key, value Value) (previous Value, loaded bool) {
+	var insert func(n *sortedMapNode[Key, Value]) *sortedMapNode[Key, Value]
+	insert = func(n *sortedMapNode[Key, Value]) *sortedMapNode[Key, Value] {
+		switch {
+		case n == nil:
+			return &sortedMapNode[Key, Value]{key: key, value: value, height: 1, size: 1}
+		case m.less(key, n.key):
+			n.left = insert(n.left)
+		case m.less(n.key, key):
+			n.right = insert(n.right)
+		default:
+			previous, loaded = n.value, true
+			n.value = value
+			return n
+		}
+		return n.balance()
+	}
+
+	m.root = insert(m.root)
+	return
+}
+
+func (m *SortedMap[Key, Value]) LoadOrStore(key Key, value Value) (Value, bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+
+	m.Store(key, value)
+	return value, false
+}
+
+func (m *SortedMap[Key, Value]) LoadOrCreate(key Key, create func() Value) (Value, bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+
+	value := create()
+	m.Store(key, value)
+	return value, false
+}
+
+func (m *SortedMap[Key, Value]) Delete(key Key) bool {
+	_, ok := m.LoadAndDelete(key)
+	return ok
+}
+
+func (m *SortedMap[Key, Value]) LoadAndDelete(key Key) (value Value, ok bool) {
+	var remove func(n *sortedMapNode[Key, Value]) *sortedMapNode[Key, Value]
+	remove = func(n *sortedMapNode[Key, Value]) *sortedMapNode[Key, Value] {
+		switch {
+		case n == nil:
+			return nil
+		case m.less(key, n.key):
+			n.left = remove(n.left)
+		case m.less(n.key, key):
+			n.right = remove(n.right)
+		default:
+			value, ok = n.value, true
+			if n.left == nil {
+				return n.right
+			}
+			if n.right == nil {
+				return n.left
+			}
+
+			var successor *sortedMapNode[Key, Value]
+			right := removeSortedMapMin(n.right, &successor)
+			successor.left, successor.right = n.left, right
+			return successor.balance()
+		}
+		return n.balance()
+	}
+
+	m.root = remove(m.root)
+	return
+}
+
+func removeSortedMapMin[Key any, Value any](n *sortedMapNode[Key, Value], removed **sortedMapNode[Key, Value]) *sortedMapNode[Key, Value] {
+	if n.left == nil {
+		*removed = n
+		return n.right
+	}
+
+	n.left = removeSortedMapMin(n.left, removed)
+	return n.balance()
+}
+
+func (m *SortedMap[Key, Value]) Min() (key Key, value Value, ok bool) {
+	n := m.root
+	if n == nil {
+		return
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+func (m *SortedMap[Key, Value]) Max() (key Key, value Value, ok bool) {
+	n := m.root
+	if n == nil {
+		return
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+func (m *SortedMap[Key, Value]) PopMin() (key Key, value Value, ok bool) {
+	if key, value, ok = m.Min(); ok {
+		m.Delete(key)
+	}
+	return
+}
+
+func (m *SortedMap[Key, Value]) PopMax() (key Key, value Value, ok bool) {
+	if key, value, ok = m.Max(); ok {
+		m.Delete(key)
+	}
+	return
+}
+
+// searchBelow 返回满足 below 的最大键（below 须单调：对较小的键成立、对较大的键不成立）
+func (m *SortedMap[Key, Value]) searchBelow(below func(Key) bool) (key Key, value Value, ok bool) {
+	for n := m.root; n != nil; {
+		if below(n.key) {
+			key, value, ok = n.key, n.value, true
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return
+}
+
+// searchAbove 返回满足 above 的最小键（above 须单调：对较大的键成立、对较小的键不成立）
+func (m *SortedMap[Key, Value]) searchAbove(above func(Key) bool) (key Key, value Value, ok bool) {
+	for n := m.root; n != nil; {
+		if above(n.key) {
+			key, value, ok = n.key, n.value, true
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return
+}
+
+// Floor 返回不大于 key 的最大键
+func (m *SortedMap[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	return m.searchBelow(func(k Key) bool { return !m.less(key, k) })
+}
+
+// Ceiling 返回不小于 key 的最小键
+func (m *SortedMap[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	return m.searchAbove(func(k Key) bool { return !m.less(k, key) })
+}
+
+// Lower 返回小于 key 的最大键
+func (m *SortedMap[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	return m.searchBelow(func(k Key) bool { return m.less(k, key) })
+}
+
+// Higher 返回大于 key 的最小键
+func (m *SortedMap[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	return m.searchAbove(func(k Key) bool { return m.less(key, k) })
+}
+
+// Rank 返回小于 key 的键的个数，即 key 存在时它的下标
+func (m *SortedMap[Key, Value]) Rank(key Key) int {
+	rank := 0
+	for n := m.root; n != nil; {
+		switch {
+		case m.less(key, n.key):
+			n = n.left
+		case m.less(n.key, key):
+			rank += n.left.getSize() + 1
+			n = n.right
+		default:
+			return rank + n.left.getSize()
+		}
+	}
+	return rank
+}
+
+// Select 返回下标为 index（从 0 开始）的键值对
+func (m *SortedMap[Key, Value]) Select(index int) (key Key, value Value, ok bool) {
+	if index < 0 || index >= m.Len() {
+		return
+	}
+
+	for n := m.root; n != nil; {
+		leftSize := n.left.getSize()
+		switch {
+		case index < leftSize:
+			n = n.left
+		case index > leftSize:
+			index -= leftSize + 1
+			n = n.right
+		default:
+			return n.key, n.value, true
+		}
+	}
+
+	return
+}
+
+// ascend 升序遍历，跳过满足 skip 的键（skip 须对较小的键成立）
+func (m *SortedMap[Key, Value]) ascend(skip func(Key) bool) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		var stack []*sortedMapNode[Key, Value]
+		for n := m.root; n != nil; {
+			if skip(n.key) {
+				n = n.right
+			} else {
+				stack = append(stack, n)
+				n = n.left
+			}
+		}
+
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if !yield(n.key, n.value) {
+				return
+			}
+
+			for n = n.right; n != nil; n = n.left {
+				stack = append(stack, n)
+			}
+		}
+	}
+}
+
+// descend 降序遍历，跳过满足 skip 的键（skip 须对较大的键成立）
+func (m *SortedMap[Key, Value]) descend(skip func(Key) bool) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		var stack []*sortedMapNode[Key, Value]
+		for n := m.root; n != nil; {
+			if skip(n.key) {
+				n = n.left
+			} else {
+				stack = append(stack, n)
+				n = n.right
+			}
+		}
+
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if !yield(n.key, n.value) {
+				return
+			}
+
+			for n = n.left; n != nil; n = n.right {
+				stack = append(stack, n)
+			}
+		}
+	}
+}
+
+func (m *SortedMap[Key, Value]) KeyValueSeq() iter.Seq2[Key, Value] {
+	return m.ascend(func(Key) bool { return false })
+}
+
+func (m *SortedMap[Key, Value]) BackwardKeyValueSeq() iter.Seq2[Key, Value] {
+	return m.descend(func(Key) bool { return false })
+}
+
+// Range 升序遍历 [from, to) 内的键值对
+func (m *SortedMap[Key, Value]) Range(from, to Key) iter.Seq2[Key, Value] {
+	return TakeWhileSeq2(m.RangeFrom(from), func(key Key, _ Value) bool {
+		return m.less(key, to)
+	})
+}
+
+// RangeFrom 从 from（含）开始升序遍历
+func (m *SortedMap[Key, Value]) RangeFrom(from Key) iter.Seq2[Key, Value] {
+	return m.ascend(func(key Key) bool { return m.less(key, from) })
+}
+
+// RangeTo 升序遍历小于 to 的键值对
+func (m *SortedMap[Key, Value]) RangeTo(to Key) iter.Seq2[Key, Value] {
+	return TakeWhileSeq2(m.KeyValueSeq(), func(key Key, _ Value) bool {
+		return m.less(key, to)
+	})
+}
+
+// DescendFrom 从 from（含）开始降序遍历
+func (m *SortedMap[Key, Value]) DescendFrom(from Key) iter.Seq2[Key, Value] {
+	return m.descend(func(key Key) bool { return m.less(from, key) })
+}
+
+func (m *SortedMap[Key, Value]) KeySeq() iter.Seq[Key] {
+	return KeySeqOf(m.KeyValueSeq())
+}
+
+func (m *SortedMap[Key, Value]) ValueSeq() iter.Seq[Value] {
+	return ValueSeqOf(m.KeyValueSeq())
+}
+
+func (m *SortedMap[Key, Value]) Keys() []Key {
+	return ReadSeq[[]Key](m.KeySeq())
+}
+
+func (m *SortedMap[Key, Value]) Values() []Value {
+	return ReadSeq[[]Value](m.ValueSeq())
+}
+
+func (m *SortedMap[Key, Value]) KeyValuePairs() KeyValuePairs[Key, Value] {
+	pairs := make(KeyValuePairs[Key, Value], 0, m.Len())
+	for key, value := range m.KeyValueSeq() {
+		pairs = append(pairs, KeyValuePair[Key, Value]{Key: key, Value: value})
+	}
+	return pairs
+}
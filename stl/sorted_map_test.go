@@ -0,0 +1,193 @@
+package stl
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedMap(t *testing.T) {
+	m := NewSortedMap[int, string]()
+	for _, i := range []int{50, 20, 80, 10, 30, 70, 90, 60} {
+		m.Store(i, strings.Repeat("x", i/10))
+	}
+
+	assert.Equal(t, 8, m.Len())
+	assert.Equal(t, []int{10, 20, 30, 50, 60, 70, 80, 90}, m.Keys())
+	assert.Equal(t, "xxx", m.Get(30))
+
+	previous, loaded := m.Swap(30, "thirty")
+	assert.True(t, loaded)
+	assert.Equal(t, "xxx", previous)
+
+	key, _, ok := m.Floor(55)
+	assert.True(t, ok)
+	assert.Equal(t, 50, key)
+
+	key, _, ok = m.Floor(50)
+	assert.True(t, ok)
+	assert.Equal(t, 50, key)
+
+	_, _, ok = m.Floor(5)
+	assert.False(t, ok)
+
+	key, _, ok = m.Ceiling(55)
+	assert.True(t, ok)
+	assert.Equal(t, 60, key)
+
+	_, _, ok = m.Ceiling(95)
+	assert.False(t, ok)
+
+	key, _, _ = m.Lower(50)
+	assert.Equal(t, 30, key)
+
+	key, _, _ = m.Higher(50)
+	assert.Equal(t, 60, key)
+
+	assert.Equal(t, 3, m.Rank(50))
+	assert.Equal(t, 4, m.Rank(55))
+	assert.Equal(t, 0, m.Rank(1))
+	assert.Equal(t, 8, m.Rank(100))
+
+	key, value, ok := m.Select(2)
+	assert.True(t, ok)
+	assert.Equal(t, 30, key)
+	assert.Equal(t, "thirty", value)
+
+	_, _, ok = m.Select(8)
+	assert.False(t, ok)
+
+	assert.Equal(t, []int{30, 50, 60}, ReadSeq[[]int](KeySeqOf(m.Range(25, 70))))
+	assert.Equal(t, []int{80, 90}, ReadSeq[[]int](KeySeqOf(m.RangeFrom(80))))
+	assert.Equal(t, []int{10, 20}, ReadSeq[[]int](KeySeqOf(m.RangeTo(30))))
+	assert.Equal(t, []int{50, 30, 20}, ReadSeq[[]int](TakeSeq(KeySeqOf(m.DescendFrom(55)), 3)))
+	assert.Equal(t, []int{90, 80}, ReadSeq[[]int](TakeSeq(KeySeqOf(m.BackwardKeyValueSeq()), 2)))
+	assert.Empty(t, ReadSeq[[]int](KeySeqOf(m.Range(51, 59))))
+
+	key, _, _ = m.PopMin()
+	assert.Equal(t, 10, key)
+	key, _, _ = m.PopMax()
+	assert.Equal(t, 90, key)
+
+	value, ok = m.LoadAndDelete(50)
+	assert.True(t, ok)
+	assert.Equal(t, "xxxxx", value)
+	assert.False(t, m.Delete(50))
+	assert.Equal(t, []int{20, 30, 60, 70, 80}, m.Keys())
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	_, _, ok = m.Min()
+	assert.False(t, ok)
+}
+
+func TestSortedMapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	m := NewSortedMap[int, int]()
+	expected := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, existed := expected[key]
+			delete(expected, key)
+			assert.Equal(t, existed, m.Delete(key))
+		} else {
+			expected[key] = i
+			m.Store(key, i)
+		}
+	}
+
+	keys := MapKeys(expected)
+	sort.Ints(keys)
+
+	assert.Equal(t, keys, m.Keys())
+	assert.Equal(t, MapValuesByKeys(expected, keys...), m.Values())
+	assert.LessOrEqual(t, m.root.getHeight(), 2*bitsLen(len(keys)))
+
+	for i, key := range keys {
+		assert.Equal(t, i, m.Rank(key))
+		selected, _, _ := m.Select(i)
+		assert.Equal(t, key, selected)
+	}
+}
+
+func bitsLen(n int) int {
+	bits := 0
+	for ; n > 0; n >>= 1 {
+		bits++
+	}
+	return bits
+}
+
+func TestSortedMapBy(t *testing.T) {
+	m := NewSortedMapBy[string, int](func(a, b string) bool {
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+
+	m.Store("Banana", 1)
+	m.Store("apple", 2)
+	m.Store("BANANA", 3)
+
+	assert.Equal(t, KeyValuePairs[string, int]{{"apple", 2}, {"Banana", 3}}, m.KeyValuePairs())
+
+	value, loaded := m.LoadOrStore("APPLE", 9)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, value)
+
+	value, loaded = m.LoadOrCreate("cherry", func() int { return 4 })
+	assert.False(t, loaded)
+	assert.Equal(t, 4, value)
+	assert.True(t, m.Contains("CHERRY"))
+}
+
+func TestSortedSet(t *testing.T) {
+	set := NewSortedSet(5, 1, 4, 1, 3)
+	assert.Equal(t, 4, set.Len())
+	assert.Equal(t, []int{1, 3, 4, 5}, set.Slice())
+	assert.True(t, set.Contain(4))
+
+	set.Add(2, 9)
+	assert.Equal(t, []int{2, 3, 4}, ReadSeq[[]int](set.Range(2, 5)))
+	assert.Equal(t, []int{9, 5, 4}, ReadSeq[[]int](TakeSeq(set.BackwardSeq(), 3)))
+	assert.Equal(t, []int{4, 3}, ReadSeq[[]int](TakeSeq(set.DescendFrom(4), 2)))
+	assert.Equal(t, []int{5, 9}, ReadSeq[[]int](set.RangeFrom(5)))
+	assert.Equal(t, []int{1, 2}, ReadSeq[[]int](set.RangeTo(3)))
+
+	floor, ok := set.Floor(8)
+	assert.True(t, ok)
+	assert.Equal(t, 5, floor)
+
+	ceiling, ok := set.Ceiling(6)
+	assert.True(t, ok)
+	assert.Equal(t, 9, ceiling)
+
+	lower, _ := set.Lower(1)
+	assert.Equal(t, 0, lower)
+	higher, _ := set.Higher(4)
+	assert.Equal(t, 5, higher)
+
+	assert.Equal(t, 4, set.Rank(5))
+	selected, _ := set.Select(0)
+	assert.Equal(t, 1, selected)
+
+	assert.True(t, set.Delete(4))
+	assert.False(t, set.Delete(4))
+
+	min, _ := set.PopMin()
+	max, _ := set.PopMax()
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 9, max)
+	assert.Equal(t, []int{2, 3, 5}, ReadSeq[[]int](set.Seq()))
+
+	desc := NewSortedSetBy(Greater[string], "b", "a", "c")
+	assert.Equal(t, []string{"c", "b", "a"}, desc.Slice())
+	first, _ := desc.Min()
+	assert.Equal(t, "c", first)
+	last, _ := desc.Max()
+	assert.Equal(t, "a", last)
+}
@@ -0,0 +1,127 @@
+package stl
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SortedSet 是有序集合，基于 SortedMap 实现，复杂度相同。
+type SortedSet[T any] struct {
+	m *SortedMap[T, struct{}]
+}
+
+func NewSortedSet[T constraints.Ordered](datas ...T) *SortedSet[T] {
+	return NewSortedSetBy(Less[T], datas...)
+}
+
+// NewSortedSetBy 使用自定义比较函数，less(a, b) 与 less(b, a) 均为 false 的元素视为同一个元素
+func NewSortedSetBy[T any](less func(a, b T) bool, datas ...T) *SortedSet[T] {
+	set := &SortedSet[T]{
+		m: NewSortedMapBy[T, struct{}](less),
+	}
+	set.Add(datas...)
+	return set
+}
+
+func (set *SortedSet[T]) Len() int {
+	return set.m.Len()
+}
+
+func (set *SortedSet[T]) Clear() {
+	set.m.Clear()
+}
+
+func (set *SortedSet[T]) Add(datas ...T) *SortedSet[T] {
+	for _, data := range datas {
+		set.m.Store(data, struct{}{})
+	}
+	return set
+}
+
+func (set *SortedSet[T]) Contain(data T) bool {
+	return set.m.Contains(data)
+}
+
+// Delete 删除 data，返回其是否存在
+func (set *SortedSet[T]) Delete(data T) bool {
+	return set.m.Delete(data)
+}
+
+func (set *SortedSet[T]) Min() (data T, ok bool) {
+	data, _, ok = set.m.Min()
+	return
+}
+
+func (set *SortedSet[T]) Max() (data T, ok bool) {
+	data, _, ok = set.m.Max()
+	return
+}
+
+func (set *SortedSet[T]) PopMin() (data T, ok bool) {
+	data, _, ok = set.m.PopMin()
+	return
+}
+
+func (set *SortedSet[T]) PopMax() (data T, ok bool) {
+	data, _, ok = set.m.PopMax()
+	return
+}
+
+func (set *SortedSet[T]) Floor(data T) (result T, ok bool) {
+	result, _, ok = set.m.Floor(data)
+	return
+}
+
+func (set *SortedSet[T]) Ceiling(data T) (result T, ok bool) {
+	result, _, ok = set.m.Ceiling(data)
+	return
+}
+
+func (set *SortedSet[T]) Lower(data T) (result T, ok bool) {
+	result, _, ok = set.m.Lower(data)
+	return
+}
+
+func (set *SortedSet[T]) Higher(data T) (result T, ok bool) {
+	result, _, ok = set.m.Higher(data)
+	return
+}
+
+func (set *SortedSet[T]) Rank(data T) int {
+	return set.m.Rank(data)
+}
+
+func (set *SortedSet[T]) Select(index int) (data T, ok bool) {
+	data, _, ok = set.m.Select(index)
+	return
+}
+
+func (set *SortedSet[T]) Seq() iter.Seq[T] {
+	return set.m.KeySeq()
+}
+
+func (set *SortedSet[T]) BackwardSeq() iter.Seq[T] {
+	return KeySeqOf(set.m.BackwardKeyValueSeq())
+}
+
+// Range 升序遍历 [from, to) 内的元素
+func (set *SortedSet[T]) Range(from, to T) iter.Seq[T] {
+	return KeySeqOf(set.m.Range(from, to))
+}
+
+func (set *SortedSet[T]) RangeFrom(from T) iter.Seq[T] {
+	return KeySeqOf(set.m.RangeFrom(from))
+}
+
+func (set *SortedSet[T]) RangeTo(to T) iter.Seq[T] {
+	return KeySeqOf(set.m.RangeTo(to))
+}
+
+func (set *SortedSet[T]) DescendFrom(from T) iter.Seq[T] {
+	return KeySeqOf(set.m.DescendFrom(from))
+}
+
+func (set *SortedSet[T]) Slice() []T {
+	return set.m.Keys()
+}
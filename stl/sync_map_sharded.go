@@ -0,0 +1,451 @@
+package stl
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/fasionchan/goutils/basic"
+)
+
+// ErrShardedSyncMapNoCreateFunc 表示 LoadOrCreate 未指定 create，也未通过 WithCreateFunc 设置创建函数
+var ErrShardedSyncMapNoCreateFunc = errors.New("stl: ShardedSyncMap has no create func")
+
+type shardedSyncMapEntry[Value any] struct {
+	value    Value
+	expireAt time.Time // 零值表示永不过期
+}
+
+func (entry shardedSyncMapEntry[Value]) expired(now time.Time) bool {
+	return !entry.expireAt.IsZero() && !now.Before(entry.expireAt)
+}
+
+type shardedSyncMapCall[Value any] struct {
+	done  chan struct{}
+	value Value
+	err   error
+}
+
+type shardedSyncMapShard[Key comparable, Value any] struct {
+	mu       sync.RWMutex
+	m        map[Key]shardedSyncMapEntry[Value]
+	creating map[Key]*shardedSyncMapCall[Value]
+}
+
+// lookup 查找未过期的值，调用方须持有锁
+func (shard *shardedSyncMapShard[Key, Value]) lookup(key Key, now time.Time) (value Value, ok bool) {
+	entry, ok := shard.m[key]
+	if !ok || entry.expired(now) {
+		return value, false
+	}
+	return entry.value, true
+}
+
+// ShardedSyncMap 是分片加锁的并发 map，接口与 SyncMap 一致：
+// 不同分片的读写互不阻塞，LoadOrCreate 只阻塞创建同一个键的调用方。
+// 键可以设置过期时间：过期的键读取时视为不存在并顺便删除，也可由 Sweep / StartSweeper 定期清理。
+type ShardedSyncMap[Key comparable, Value any] struct {
+	shards     []*shardedSyncMapShard[Key, Value]
+	seed       maphash.Seed
+	ttl        time.Duration
+	createFunc func(context.Context, Key) (Value, error)
+}
+
+// NewShardedSyncMap 创建有 shards 个分片的 map，shards <= 0 时按 CPU 个数决定；分片数会向上取整为 2 的幂
+func NewShardedSyncMap[Key comparable, Value any](shards int) *ShardedSyncMap[Key, Value] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0) * 4
+	}
+
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+
+	m := &ShardedSyncMap[Key, Value]{
+		shards: make([]*shardedSyncMapShard[Key, Value], n),
+		seed:   maphash.MakeSeed(),
+	}
+
+	for i := range m.shards {
+		m.shards[i] = &shardedSyncMapShard[Key, Value]{
+			m:        map[Key]shardedSyncMapEntry[Value]{},
+			creating: map[Key]*shardedSyncMapCall[Value]{},
+		}
+	}
+
+	return m
+}
+
+// WithTTL 设置 Store、LoadOrStore、LoadOrCreate 等写入的默认过期时间，ttl <= 0 表示永不过期
+func (m *ShardedSyncMap[Key, Value]) WithTTL(ttl time.Duration) *ShardedSyncMap[Key, Value] {
+	m.ttl = ttl
+	return m
+}
+
+// WithCreateFunc 设置 LoadOrCreate 未指定 create 时使用的创建函数
+func (m *ShardedSyncMap[Key, Value]) WithCreateFunc(createFunc func(context.Context, Key) (Value, error)) *ShardedSyncMap[Key, Value] {
+	m.createFunc = createFunc
+	return m
+}
+
+func (m *ShardedSyncMap[Key, Value]) shard(key Key) *shardedSyncMapShard[Key, Value] {
+	return m.shards[maphash.Comparable(m.seed, key)&uint64(len(m.shards)-1)]
+}
+
+func (m *ShardedSyncMap[Key, Value]) newEntry(value Value, ttl time.Duration, now time.Time) shardedSyncMapEntry[Value] {
+	entry := shardedSyncMapEntry[Value]{value: value}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+	return entry
+}
+
+func (m *ShardedSyncMap[Key, Value]) Clear() {
+	for _, shard := range m.shards {
+		shard.mu.Lock()
+		clear(shard.m)
+		shard.mu.Unlock()
+	}
+}
+
+// Len 返回未过期的键个数
+func (m *ShardedSyncMap[Key, Value]) Len() (n int) {
+	now := time.Now()
+	for _, shard := range m.shards {
+		shard.mu.RLock()
+		for _, entry := range shard.m {
+			if !entry.expired(now) {
+				n++
+			}
+		}
+		shard.mu.RUnlock()
+	}
+	return
+}
+
+func (m *ShardedSyncMap[Key, Value]) Empty() bool {
+	return m.Len() == 0
+}
+
+func (m *ShardedSyncMap[Key, Value]) Delete(key Key) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	delete(shard.m, key)
+}
+
+func (m *ShardedSyncMap[Key, Value]) Get(key Key) Value {
+	value, _ := m.Load(key)
+	return value
+}
+
+func (m *ShardedSyncMap[Key, Value]) Load(key Key) (value Value, loaded bool) {
+	shard := m.shard(key)
+	now := time.Now()
+
+	shard.mu.RLock()
+	entry, exists := shard.m[key]
+	shard.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	if entry.expired(now) {
+		// 惰性删除，加写锁后须再次确认没有被重新写入
+		shard.mu.Lock()
+		if entry, exists := shard.m[key]; exists && entry.expired(now) {
+			delete(shard.m, key)
+		}
+		shard.mu.Unlock()
+		return
+	}
+
+	return entry.value, true
+}
+
+// LoadOrCreate 键不存在时调用 create（为 nil 时使用 WithCreateFunc 设置的函数）创建并保存。
+// 同一个键同时只有一个调用方执行创建，其他调用方等待其结果；ctx 结束时等待的调用方返回 ctx.Err()。
+// 创建失败时不保存，等待的调用方得到相同的错误；没有可用的创建函数时返回 ErrShardedSyncMapNoCreateFunc。
+func (m *ShardedSyncMap[Key, Value]) LoadOrCreate(ctx context.Context, key Key, create func(ctx context.Context, key Key) (Value, error)) (value Value, loaded bool, err error) {
+	shard := m.shard(key)
+
+	if create == nil {
+		create = m.createFunc
+	}
+
+	shard.mu.Lock()
+	if value, loaded = shard.lookup(key, time.Now()); loaded {
+		shard.mu.Unlock()
+		return
+	}
+
+	if call, ok := shard.creating[key]; ok {
+		shard.mu.Unlock()
+
+		ctx = chanContext(ctx)
+		select {
+		case <-ctx.Done():
+			return value, false, ctx.Err()
+		case <-call.done:
+			return call.value, call.err == nil, call.err
+		}
+	}
+
+	if create == nil {
+		shard.mu.Unlock()
+		return value, false, ErrShardedSyncMapNoCreateFunc
+	}
+
+	call := &shardedSyncMapCall[Value]{done: make(chan struct{})}
+	shard.creating[key] = call
+	shard.mu.Unlock()
+
+	defer func() {
+		shard.mu.Lock()
+		delete(shard.creating, key)
+		if err == nil {
+			shard.m[key] = m.newEntry(value, m.ttl, time.Now())
+		}
+		shard.mu.Unlock()
+
+		call.value, call.err = value, err
+		close(call.done)
+	}()
+
+	// create panic 时转为错误，避免等待的调用方永远阻塞
+	defer basic.RecoverPanic(&err)
+
+	value, err = create(ctx, key)
+	return
+}
+
+func (m *ShardedSyncMap[Key, Value]) LoadOrCreateLite(ctx context.Context, key Key, create func() Value) (Value, bool) {
+	value, loaded, _ := m.LoadOrCreate(ctx, key, func(context.Context, Key) (Value, error) {
+		return create(), nil
+	})
+	return value, loaded
+}
+
+func (m *ShardedSyncMap[Key, Value]) LoadOrStore(key Key, newValue Value) (value Value, loaded bool) {
+	shard := m.shard(key)
+	now := time.Now()
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if value, loaded = shard.lookup(key, now); loaded {
+		return
+	}
+
+	shard.m[key] = m.newEntry(newValue, m.ttl, now)
+	return newValue, false
+}
+
+func (m *ShardedSyncMap[Key, Value]) LoadAndDelete(key Key) (value Value, loaded bool) {
+	shard := m.shard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	value, loaded = shard.lookup(key, time.Now())
+	delete(shard.m, key)
+	return
+}
+
+func (m *ShardedSyncMap[Key, Value]) Store(key Key, value Value) {
+	m.StoreWithTTL(key, value, m.ttl)
+}
+
+// StoreWithTTL 保存并指定过期时间，ttl <= 0 表示永不过期
+func (m *ShardedSyncMap[Key, Value]) StoreWithTTL(key Key, value Value, ttl time.Duration) {
+	shard := m.shard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.m[key] = m.newEntry(value, ttl, time.Now())
+}
+
+func (m *ShardedSyncMap[Key, Value]) StoreOk(key Key, value Value) (ok bool) {
+	if m == nil {
+		return false
+	}
+
+	m.Store(key, value)
+	return true
+}
+
+func (m *ShardedSyncMap[Key, Value]) Swap(key Key, value Value) (previous Value, loaded bool) {
+	shard := m.shard(key)
+	now := time.Now()
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	previous, loaded = shard.lookup(key, now)
+	shard.m[key] = m.newEntry(value, m.ttl, now)
+	return
+}
+
+func (m *ShardedSyncMap[Key, Value]) SwapOk(key Key, value Value) (previous Value, loaded bool, ok bool) {
+	if m == nil {
+		return
+	}
+
+	previous, loaded = m.Swap(key, value)
+	return previous, loaded, true
+}
+
+// Expire 重新设置已有键的过期时间，ttl <= 0 表示永不过期；键不存在或已过期时返回 false
+func (m *ShardedSyncMap[Key, Value]) Expire(key Key, ttl time.Duration) bool {
+	shard := m.shard(key)
+	now := time.Now()
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	value, ok := shard.lookup(key, now)
+	if ok {
+		shard.m[key] = m.newEntry(value, ttl, now)
+	}
+	return ok
+}
+
+// TTL 返回键的剩余时间，永不过期时 ttl 为 0
+func (m *ShardedSyncMap[Key, Value]) TTL(key Key) (ttl time.Duration, ok bool) {
+	shard := m.shard(key)
+	now := time.Now()
+
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	entry, ok := shard.m[key]
+	if !ok || entry.expired(now) {
+		return 0, false
+	}
+
+	if !entry.expireAt.IsZero() {
+		ttl = entry.expireAt.Sub(now)
+	}
+
+	return ttl, true
+}
+
+// Sweep 删除全部已过期的键，返回删除的个数
+func (m *ShardedSyncMap[Key, Value]) Sweep() (n int) {
+	now := time.Now()
+	for _, shard := range m.shards {
+		shard.mu.Lock()
+		for key, entry := range shard.m {
+			if entry.expired(now) {
+				delete(shard.m, key)
+				n++
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return
+}
+
+// StartSweeper 启动后台协程每隔 interval 调用一次 Sweep，ctx 结束后退出；interval <= 0 时不启动
+func (m *ShardedSyncMap[Key, Value]) StartSweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ctx = chanContext(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Sweep()
+			}
+		}
+	}()
+}
+
+// Range 返回遍历快照的 Seq2：开始遍历时各分片依次加读锁复制，遍历过程中的修改不影响遍历，也不阻塞其他调用方
+func (m *ShardedSyncMap[Key, Value]) Range() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for key, value := range m.ToMapping() {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// ToMapping 返回未过期键值的快照
+func (m *ShardedSyncMap[Key, Value]) ToMapping() Mapping[Key, Value] {
+	now := time.Now()
+	result := Mapping[Key, Value]{}
+	for _, shard := range m.shards {
+		shard.mu.RLock()
+		for key, entry := range shard.m {
+			if !entry.expired(now) {
+				result[key] = entry.value
+			}
+		}
+		shard.mu.RUnlock()
+	}
+	return result
+}
+
+// Native 返回未过期键值的快照；与 SyncMap.Native 不同，修改返回的 map 不影响 m
+func (m *ShardedSyncMap[Key, Value]) Native() map[Key]Value {
+	return m.ToMapping()
+}
+
+// SwapMapping 以 new 替换全部内容（按默认过期时间写入），返回原有未过期键值的快照。
+// 替换期间锁住全部分片，其他调用方看不到替换了一半的状态
+func (m *ShardedSyncMap[Key, Value]) SwapMapping(new Mapping[Key, Value]) Mapping[Key, Value] {
+	if m == nil {
+		return nil
+	}
+
+	for _, shard := range m.shards {
+		shard.mu.Lock()
+	}
+
+	now := time.Now()
+	old := Mapping[Key, Value]{}
+	for _, shard := range m.shards {
+		for key, entry := range shard.m {
+			if !entry.expired(now) {
+				old[key] = entry.value
+			}
+		}
+		clear(shard.m)
+	}
+
+	for key, value := range new {
+		m.shard(key).m[key] = m.newEntry(value, m.ttl, now)
+	}
+
+	for _, shard := range m.shards {
+		shard.mu.Unlock()
+	}
+
+	return old
+}
+
+func (m *ShardedSyncMap[Key, Value]) Keys() []Key {
+	return MapKeys(m.ToMapping())
+}
+
+func (m *ShardedSyncMap[Key, Value]) Values() []Value {
+	return m.ToMapping().Values()
+}
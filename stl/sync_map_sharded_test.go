@@ -0,0 +1,221 @@
+package stl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedSyncMap(t *testing.T) {
+	m := NewShardedSyncMap[string, int](3)
+	assert.Equal(t, 4, len(m.shards))
+	assert.True(t, m.Empty())
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	assert.Equal(t, 1, m.Get("a"))
+	assert.Equal(t, 2, m.Len())
+
+	value, loaded := m.LoadOrStore("a", 10)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+	value, loaded = m.LoadOrStore("c", 3)
+	assert.False(t, loaded)
+	assert.Equal(t, 3, value)
+
+	previous, loaded := m.Swap("c", 30)
+	assert.True(t, loaded)
+	assert.Equal(t, 3, previous)
+
+	value, loaded = m.LoadAndDelete("b")
+	assert.True(t, loaded)
+	assert.Equal(t, 2, value)
+	_, loaded = m.Load("b")
+	assert.False(t, loaded)
+
+	assert.ElementsMatch(t, []string{"a", "c"}, m.Keys())
+	assert.ElementsMatch(t, []int{1, 30}, m.Values())
+	assert.Equal(t, Mapping[string, int]{"a": 1, "c": 30}, m.ToMapping())
+
+	m.Clear()
+	assert.True(t, m.Empty())
+}
+
+func TestShardedSyncMapTTL(t *testing.T) {
+	m := NewShardedSyncMap[string, int](0).WithTTL(20 * time.Millisecond)
+	m.Store("short", 1)
+	m.StoreWithTTL("forever", 2, 0)
+	m.StoreWithTTL("long", 3, time.Hour)
+
+	ttl, ok := m.TTL("forever")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), ttl)
+	ttl, ok = m.TTL("long")
+	assert.True(t, ok)
+	assert.Greater(t, ttl, 59*time.Minute)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = m.Load("short")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+
+	// 惰性删除后 Sweep 无需再删
+	assert.Equal(t, 0, m.Sweep())
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	assert.True(t, m.Expire("b", 0))
+	assert.False(t, m.Expire("missing", 0))
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, m.Sweep())
+	assert.ElementsMatch(t, []string{"forever", "long", "b"}, m.Keys())
+}
+
+func TestShardedSyncMapSweeper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewShardedSyncMap[int, int](1).WithTTL(5 * time.Millisecond)
+	m.StartSweeper(ctx, 5*time.Millisecond)
+	for i := range 10 {
+		m.Store(i, i)
+	}
+
+	assert.Eventually(t, func() bool {
+		m.shards[0].mu.RLock()
+		defer m.shards[0].mu.RUnlock()
+		return len(m.shards[0].m) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestShardedSyncMapLoadOrCreate(t *testing.T) {
+	m := NewShardedSyncMap[string, int](8)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	create := func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return len(key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, _ = m.LoadOrCreate(context.Background(), "slow", create)
+		}()
+	}
+
+	// 其他键的创建不被阻塞
+	value, loaded, err := m.LoadOrCreate(context.Background(), "fast", func(context.Context, string) (int, error) {
+		return 1, nil
+	})
+	assert.Nil(t, err)
+	assert.False(t, loaded)
+	assert.Equal(t, 1, value)
+
+	// 等待中的调用方可被 ctx 取消
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = m.LoadOrCreate(ctx, "slow", create)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, []int{4, 4, 4, 4, 4, 4, 4, 4, 4, 4}, results)
+}
+
+func TestShardedSyncMapLoadOrCreateError(t *testing.T) {
+	m := NewShardedSyncMap[string, int](1).WithCreateFunc(func(ctx context.Context, key string) (int, error) {
+		return 0, errors.New("boom")
+	})
+
+	_, loaded, err := m.LoadOrCreate(context.Background(), "a", nil)
+	assert.False(t, loaded)
+	assert.EqualError(t, err, "boom")
+	assert.True(t, m.Empty())
+
+	_, _, err = m.LoadOrCreate(context.Background(), "a", func(context.Context, string) (int, error) {
+		panic("oops")
+	})
+	assert.ErrorContains(t, err, "oops")
+
+	value, loaded := m.LoadOrCreateLite(context.Background(), "a", func() int { return 7 })
+	assert.False(t, loaded)
+	assert.Equal(t, 7, value)
+}
+
+func TestShardedSyncMapNoCreateFunc(t *testing.T) {
+	m := NewShardedSyncMap[string, int](1)
+
+	_, loaded, err := m.LoadOrCreate(context.Background(), "a", nil)
+	assert.False(t, loaded)
+	assert.ErrorIs(t, err, ErrShardedSyncMapNoCreateFunc)
+	assert.True(t, m.Empty())
+
+	// interval <= 0 时不启动，也不 panic
+	m.StartSweeper(context.Background(), 0)
+	m.StartSweeper(context.Background(), -time.Second)
+}
+
+func TestShardedSyncMapSwapMapping(t *testing.T) {
+	m := NewShardedSyncMap[int, int](4)
+	for i := range 10 {
+		m.Store(i, i)
+	}
+	m.StoreWithTTL(100, 100, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	native := m.Native()
+	assert.Len(t, native, 10)
+	native[50] = 50
+	_, ok := m.Load(50)
+	assert.False(t, ok)
+
+	old := m.SwapMapping(Mapping[int, int]{20: 20, 21: 21})
+	delete(native, 50)
+	assert.Equal(t, Mapping[int, int](native), old)
+	assert.ElementsMatch(t, []int{20, 21}, m.Keys())
+
+	var nilMap *ShardedSyncMap[int, int]
+	assert.Nil(t, nilMap.SwapMapping(nil))
+}
+
+func TestShardedSyncMapRange(t *testing.T) {
+	m := NewShardedSyncMap[int, int](4)
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+
+	seen := map[int]int{}
+	for key, value := range m.Range() {
+		// 遍历中修改不影响快照
+		m.Delete(key + 1)
+		seen[key] = value
+	}
+	assert.Equal(t, 100, len(seen))
+	assert.Equal(t, 81, seen[9])
+
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+
+	n := 0
+	for range m.Range() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, n)
+}
@@ -0,0 +1,288 @@
+package stl
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+type tdigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest 以有限内存流式估计分位数，两端（如 p99、p999）的精度高于中间；
+// 多个实例可以合并，适合分别采集延迟后汇总统计。
+// compression 越大越精确，质心个数约为 compression 的数倍。非并发安全。
+type TDigest struct {
+	compression float64
+	centroids   []tdigestCentroid
+	buffer      []tdigestCentroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+// NewTDigest 创建 TDigest，compression <= 0 时取 100
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = 100
+	}
+
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// TDigestOf 创建 TDigest 并加入 numbers
+func TDigestOf[Data Number](compression float64, numbers ...Data) *TDigest {
+	digest := NewTDigest(compression)
+	for _, number := range numbers {
+		digest.Add(float64(number))
+	}
+	return digest
+}
+
+func (digest *TDigest) Compression() float64 {
+	return digest.compression
+}
+
+// Count 返回加入的总权重，即未加权时的个数
+func (digest *TDigest) Count() float64 {
+	return digest.count
+}
+
+func (digest *TDigest) Min() float64 {
+	if digest.count == 0 {
+		return 0
+	}
+	return digest.min
+}
+
+func (digest *TDigest) Max() float64 {
+	if digest.count == 0 {
+		return 0
+	}
+	return digest.max
+}
+
+func (digest *TDigest) Add(value float64) {
+	digest.AddWeighted(value, 1)
+}
+
+// AddWeighted 加入权重为 weight 的值，NaN 与非正权重被忽略
+func (digest *TDigest) AddWeighted(value, weight float64) {
+	if math.IsNaN(value) || weight <= 0 {
+		return
+	}
+
+	digest.buffer = append(digest.buffer, tdigestCentroid{mean: value, weight: weight})
+	digest.count += weight
+	digest.min = min(digest.min, value)
+	digest.max = max(digest.max, value)
+
+	if len(digest.buffer) >= int(digest.compression)*5 {
+		digest.compress()
+	}
+}
+
+// scale 为 k1 尺度函数，使两端的质心更小
+func (digest *TDigest) scale(q float64) float64 {
+	return digest.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (digest *TDigest) scaleInverse(k float64) float64 {
+	return (math.Sin(min(k*2*math.Pi/digest.compression, math.Pi/2)) + 1) / 2
+}
+
+func (digest *TDigest) compress() {
+	if len(digest.buffer) == 0 {
+		return
+	}
+
+	all := append(digest.centroids, digest.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]tdigestCentroid, 0, len(digest.centroids)+1)
+	current := all[0]
+	var before float64
+	limit := digest.scaleInverse(digest.scale(0) + 1)
+
+	for _, centroid := range all[1:] {
+		if (before+current.weight+centroid.weight)/digest.count <= limit {
+			current.weight += centroid.weight
+			current.mean += (centroid.mean - current.mean) * centroid.weight / current.weight
+			continue
+		}
+
+		merged = append(merged, current)
+		before += current.weight
+		current = centroid
+		limit = digest.scaleInverse(digest.scale(before/digest.count) + 1)
+	}
+
+	digest.centroids = append(merged, current)
+	digest.buffer = nil
+}
+
+// Quantile 估计第 q 分位数（q 取 0~1），质心之间线性插值；没有数据时为 0
+func (digest *TDigest) Quantile(q float64) float64 {
+	digest.compress()
+
+	centroids := digest.centroids
+	if len(centroids) == 0 {
+		return 0
+	}
+
+	if q <= 0 {
+		return digest.min
+	} else if q >= 1 {
+		return digest.max
+	}
+
+	// 每个质心的权重视为以其均值为中心分布，首尾分别与 min、max 插值
+	target := q * digest.count
+	if first := centroids[0]; target < first.weight/2 {
+		return digest.min + (first.mean-digest.min)*target/(first.weight/2)
+	}
+
+	var before float64
+	for i := 0; i < len(centroids)-1; i++ {
+		left, right := centroids[i], centroids[i+1]
+		leftCenter := before + left.weight/2
+		rightCenter := before + left.weight + right.weight/2
+		if target < rightCenter {
+			return left.mean + (right.mean-left.mean)*(target-leftCenter)/(rightCenter-leftCenter)
+		}
+		before += left.weight
+	}
+
+	last := centroids[len(centroids)-1]
+	lastCenter := digest.count - last.weight/2
+	if target <= lastCenter || last.weight == 0 {
+		return last.mean
+	}
+
+	return last.mean + (digest.max-last.mean)*(target-lastCenter)/(last.weight/2)
+}
+
+// Percentile 估计第 p 百分位数（p 取 0~100）
+func (digest *TDigest) Percentile(p float64) float64 {
+	return digest.Quantile(p / 100)
+}
+
+func (digest *TDigest) Median() float64 {
+	return digest.Quantile(0.5)
+}
+
+// CDF 估计不大于 value 的比例
+func (digest *TDigest) CDF(value float64) float64 {
+	digest.compress()
+
+	centroids := digest.centroids
+	if len(centroids) == 0 {
+		return 0
+	} else if value < digest.min {
+		return 0
+	} else if value >= digest.max {
+		return 1
+	}
+
+	if first := centroids[0]; value < first.mean {
+		if first.mean == digest.min {
+			return 0
+		}
+		return first.weight / 2 * (value - digest.min) / (first.mean - digest.min) / digest.count
+	}
+
+	var before float64
+	for i := 0; i < len(centroids)-1; i++ {
+		left, right := centroids[i], centroids[i+1]
+		if value < right.mean {
+			leftCenter := before + left.weight/2
+			rightCenter := before + left.weight + right.weight/2
+			return (leftCenter + (rightCenter-leftCenter)*(value-left.mean)/(right.mean-left.mean)) / digest.count
+		}
+		before += left.weight
+	}
+
+	last := centroids[len(centroids)-1]
+	lastCenter := digest.count - last.weight/2
+	return (lastCenter + last.weight/2*(value-last.mean)/(digest.max-last.mean)) / digest.count
+}
+
+// Merge 合并另一个 TDigest 的数据，other 不变
+func (digest *TDigest) Merge(other *TDigest) {
+	if other.count == 0 {
+		return
+	}
+
+	digest.buffer = append(digest.buffer, other.centroids...)
+	digest.buffer = append(digest.buffer, other.buffer...)
+	digest.count += other.count
+	digest.min = min(digest.min, other.min)
+	digest.max = max(digest.max, other.max)
+	digest.compress()
+}
+
+func (digest *TDigest) Reset() {
+	digest.centroids, digest.buffer = nil, nil
+	digest.count = 0
+	digest.min, digest.max = math.Inf(1), math.Inf(-1)
+}
+
+const tdigestMagic = "tdg\x01"
+
+func (digest *TDigest) MarshalBinary() ([]byte, error) {
+	digest.compress()
+
+	data := make([]byte, 0, len(tdigestMagic)+34+len(digest.centroids)*16)
+	data = append(data, tdigestMagic...)
+	for _, value := range []float64{digest.compression, digest.min, digest.max} {
+		data = binary.BigEndian.AppendUint64(data, math.Float64bits(value))
+	}
+	data = binary.AppendUvarint(data, uint64(len(digest.centroids)))
+	for _, centroid := range digest.centroids {
+		data = binary.BigEndian.AppendUint64(data, math.Float64bits(centroid.mean))
+		data = binary.BigEndian.AppendUint64(data, math.Float64bits(centroid.weight))
+	}
+	return data, nil
+}
+
+func (digest *TDigest) UnmarshalBinary(data []byte) error {
+	reader := newSketchReader(data, tdigestMagic)
+	float := func() float64 {
+		bytes := reader.bytes(8)
+		if bytes == nil {
+			return 0
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(bytes))
+	}
+
+	compression, minValue, maxValue := float(), float(), float()
+	n := reader.uvarint()
+	// 用除法比较以免 n*16 溢出
+	if reader.err == nil && (compression <= 0 || n > uint64(len(reader.data))/16 || n*16 != uint64(len(reader.data))) {
+		return ErrSketchCorrupted
+	}
+
+	var count float64
+	centroids := make([]tdigestCentroid, n)
+	for i := range centroids {
+		centroids[i] = tdigestCentroid{mean: float(), weight: float()}
+		if centroids[i].weight <= 0 || i > 0 && centroids[i].mean < centroids[i-1].mean {
+			return ErrSketchCorrupted
+		}
+		count += centroids[i].weight
+	}
+
+	if err := reader.finish(); err != nil {
+		return err
+	}
+
+	digest.compression, digest.min, digest.max = compression, minValue, maxValue
+	digest.centroids, digest.buffer, digest.count = centroids, nil, count
+	return nil
+}
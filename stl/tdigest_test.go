@@ -0,0 +1,82 @@
+package stl
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTDigest(t *testing.T) {
+	digest := NewTDigest(100)
+	assert.Equal(t, 0.0, digest.Quantile(0.5))
+
+	for i := 1; i <= 10000; i++ {
+		digest.Add(float64(i))
+	}
+
+	assert.Equal(t, 10000.0, digest.Count())
+	assert.Equal(t, 1.0, digest.Min())
+	assert.Equal(t, 10000.0, digest.Max())
+	assert.Equal(t, 1.0, digest.Quantile(0))
+	assert.Equal(t, 10000.0, digest.Quantile(1))
+	assert.InDelta(t, 5000, digest.Median(), 50)
+	assert.InDelta(t, 9900, digest.Percentile(99), 10)
+	assert.InDelta(t, 9990, digest.Percentile(99.9), 2)
+	assert.InDelta(t, 10, digest.Percentile(0.1), 2)
+	assert.InDelta(t, 0.25, digest.CDF(2500), 0.005)
+	assert.Equal(t, 0.0, digest.CDF(0))
+	assert.Equal(t, 1.0, digest.CDF(10000))
+
+	assert.Less(t, len(digest.centroids), 200)
+}
+
+func TestTDigestMerge(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+
+	var all Numbers[float64]
+	merged := NewTDigest(100)
+	for range 4 {
+		var numbers Numbers[float64]
+		for range 5000 {
+			numbers = append(numbers, random.ExpFloat64()*100)
+		}
+		all = append(all, numbers...)
+		merged.Merge(TDigestOf(100, numbers...))
+	}
+
+	assert.Equal(t, 20000.0, merged.Count())
+	assert.Equal(t, all.Min(), merged.Min())
+	assert.Equal(t, all.Max(), merged.Max())
+	for _, p := range []float64{50, 90, 99} {
+		assert.InDelta(t, all.Percentile(p), merged.Percentile(p), all.Percentile(p)*0.02, "p%v", p)
+	}
+}
+
+func TestTDigestMarshalBinary(t *testing.T) {
+	digest := TDigestOf(50, Numbers[int]{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}...)
+	data, err := digest.MarshalBinary()
+	assert.Nil(t, err)
+
+	restored := NewTDigest(0)
+	assert.Nil(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, digest.Compression(), restored.Compression())
+	assert.Equal(t, digest.Count(), restored.Count())
+	assert.Equal(t, digest.Median(), restored.Median())
+
+	assert.ErrorIs(t, restored.UnmarshalBinary(data[:len(data)-1]), ErrSketchCorrupted)
+	assert.ErrorIs(t, restored.UnmarshalBinary([]byte("bad")), ErrSketchCorrupted)
+
+	// n*16 溢出后恰好等于剩余长度
+	crafted := []byte(tdigestMagic)
+	for _, value := range []float64{50, 1, 1} {
+		crafted = binary.BigEndian.AppendUint64(crafted, math.Float64bits(value))
+	}
+	crafted = binary.AppendUvarint(crafted, 1<<60+1)
+	crafted = binary.BigEndian.AppendUint64(crafted, math.Float64bits(1))
+	crafted = binary.BigEndian.AppendUint64(crafted, math.Float64bits(1))
+	assert.ErrorIs(t, restored.UnmarshalBinary(crafted), ErrSketchCorrupted)
+	assert.Equal(t, digest.Count(), restored.Count())
+}
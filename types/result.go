@@ -17,10 +17,11 @@ type (
 var (
 	NewBlankResponseResult = NewBlankTypedResponseResult[any]
 
-	NewResponseResult              = NewTypedResponseResult[any]
-	NewResponseResultFromData      = NewTypedResponseResultFromData[any]
-	NewResponseResultFromError     = NewTypedResponseResultFromError[any]
-	NewResponseResultFromPagedData = NewTypedResponseResultFromPagedData[any]
+	NewResponseResult               = NewTypedResponseResult[any]
+	NewResponseResultFromData       = NewTypedResponseResultFromData[any]
+	NewResponseResultFromError      = NewTypedResponseResultFromError[any]
+	NewResponseResultFromCodedError = NewTypedResponseResultFromCodedError[any]
+	NewResponseResultFromPagedData  = NewTypedResponseResultFromPagedData[any]
 )
 
 type TypedResponseResult[T any] struct {
@@ -67,6 +68,16 @@ func NewTypedResponseResultFromError[T any](code int, err error, msg string) *Ty
 	return NewTypedResponseResult(false, code, errstr, msg, zero, nil, "")
 }
 
+// NewTypedResponseResultFromCodedError 错误码取自 stl.WrapErrorCode 附加的错误码，没有时使用 defaultCode
+func NewTypedResponseResultFromCodedError[T any](err error, defaultCode int) *TypedResponseResult[T] {
+	code, ok := stl.ErrorCodeOf(err)
+	if !ok {
+		code = defaultCode
+	}
+
+	return NewTypedResponseResultFromError[T](code, err, "")
+}
+
 func NewTypedResponseResultFromData[T any](data T) *TypedResponseResult[T] {
 	return NewTypedResponseResultFromPagedData(data, nil)
 }
@@ -179,6 +190,17 @@ func (result *TypedResponseResult[T]) WithFail(code int, err, msg string) *Typed
 	return result
 }
 
+// WithCodedError 按 NewTypedResponseResultFromCodedError 的规则设置失败信息
+func (result *TypedResponseResult[T]) WithCodedError(err error, defaultCode int) *TypedResponseResult[T] {
+	code, ok := stl.ErrorCodeOf(err)
+	if !ok {
+		code = defaultCode
+	}
+
+	errstr := err.Error()
+	return result.WithFail(code, errstr, errstr)
+}
+
 func (result *TypedResponseResult[T]) WithSuccessData(data T) *TypedResponseResult[T] {
 	if result == nil {
 		return nil
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fasionchan/goutils/stl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseResultFromCodedError(t *testing.T) {
+	err := stl.NewErrors(errors.New("bad input"), stl.WrapErrorCode(errors.New("not found"), 404))
+
+	result := NewResponseResultFromCodedError(err, 500)
+	assert.False(t, result.Success)
+	assert.Equal(t, 404, result.Code)
+	assert.Equal(t, err.Error(), result.Error)
+	assert.Equal(t, err.Error(), result.Message)
+
+	result = NewBlankResponseResult().WithCodedError(errors.New("boom"), 500)
+	assert.False(t, result.Success)
+	assert.Equal(t, 500, result.Code)
+	assert.Equal(t, "boom", result.Message)
+}